@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/mediacoin-pro/core/chain/bcstore"
 	"github.com/mediacoin-pro/core/chain/replication"
@@ -42,8 +46,18 @@ func main() {
 	}
 	var bc = bcstore.NewChainStorage(*argDataDir+"/bc", nil)
 
-	go restsrv.StartServer(restCfg, bc)
+	restSrv := restsrv.NewService(restCfg, bc)
+	restSrv.Version = applicationName
+	go restSrv.Start()
 	go replication.Start(bc)
 
-	select {}
+	//---- wait for termination; drain in-flight REST requests --------
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := restSrv.Shutdown(ctx); err != nil {
+		xlog.Error.Printf("rest> shutdown: %v", err)
+	}
 }
@@ -0,0 +1,102 @@
+package restsrv
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	contentTypeOctetStream  = "application/octet-stream"
+	contentTypeVendorBinary = "application/vnd.mediacoin.bin"
+)
+
+// response formats
+const (
+	formatJSON = iota
+	formatBinary
+	formatProtobuf
+	formatNDJSON
+)
+
+var acceptFormats = map[string]int{
+	"application/json":      formatJSON,
+	"application/*":         formatJSON,
+	"*/*":                   formatJSON,
+	contentTypeBinary:       formatBinary, // legacy "Accept: binary"
+	contentTypeOctetStream:  formatBinary,
+	contentTypeVendorBinary: formatBinary,
+	contentTypeProtobuf:     formatProtobuf,
+	contentTypeNDJSON:       formatNDJSON,
+}
+
+// acceptedMedia is response format negotiated by header Accept
+type acceptedMedia struct {
+	format    int
+	mediaType string // content-type of response
+}
+
+// negotiateFormat returns the best response format of header Accept by quality values ("q").
+// Of media types with equal quality explicit types are preferred to wildcards, then the first listed one.
+// Unknown media types are ignored; JSON is returned by default.
+func negotiateFormat(accept string) acceptedMedia {
+	best, bestQ, bestExplicit := acceptedMedia{formatJSON, contentTypeJSON}, 0.0, false
+	for _, s := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(s))
+		if err != nil {
+			continue
+		}
+		format, ok := acceptFormats[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		explicit := !strings.HasSuffix(mediaType, "/*")
+		if q <= 0 || q < bestQ || q == bestQ && (bestExplicit || !explicit) {
+			continue
+		}
+		best, bestQ, bestExplicit = acceptedMedia{format, mediaType}, q, explicit
+		if format == formatJSON {
+			best.mediaType = contentTypeJSON
+		}
+	}
+	return best
+}
+
+// accepted returns response format negotiated by header Accept of request
+func (c *Context) accepted() acceptedMedia {
+	if c.accept == nil {
+		m := negotiateFormat(c.req.Header.Get("Accept"))
+		c.accept = &m
+	}
+	return *c.accept
+}
+
+// wantsBinary returns true if client accepts node binary format
+// ("Accept: application/octet-stream", "application/vnd.mediacoin.bin" or legacy "binary")
+func (c *Context) wantsBinary() bool {
+	return c.accepted().format == formatBinary
+}
+
+func (c *Context) wantsProtobuf() bool {
+	return c.accepted().format == formatProtobuf
+}
+
+func (c *Context) wantsNDJSON() bool {
+	return c.accepted().format == formatNDJSON
+}
+
+// binaryContentType returns content-type of binary response (media type accepted by client)
+func (c *Context) binaryContentType() string {
+	return c.accepted().mediaType
+}
+
+// isBinaryContentType returns true if content-type is one of node binary format
+func isBinaryContentType(contentType string) bool {
+	return acceptFormats[contentType] == formatBinary
+}
@@ -0,0 +1,24 @@
+package restsrv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	for accept, exp := range map[string]acceptedMedia{
+		"":                                    {formatJSON, contentTypeJSON},
+		"*/*":                                 {formatJSON, contentTypeJSON},
+		"text/html":                           {formatJSON, contentTypeJSON},
+		"binary":                              {formatBinary, contentTypeBinary},
+		"application/octet-stream":            {formatBinary, contentTypeOctetStream},
+		"application/json; charset=utf-8":     {formatJSON, contentTypeJSON},
+		"application/json;q=0.5, binary":      {formatBinary, contentTypeBinary},
+		"application/vnd.mediacoin.bin, */*":  {formatBinary, contentTypeVendorBinary},
+		"*/*, application/x-protobuf":         {formatProtobuf, contentTypeProtobuf},
+		"application/x-ndjson;q=0, */*;q=0.1": {formatJSON, contentTypeJSON},
+	} {
+		assert.Equal(t, exp, negotiateFormat(accept), accept)
+	}
+}
@@ -0,0 +1,86 @@
+package restsrv
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/common/bignum"
+)
+
+const maxActivityBuckets = 400
+
+var (
+	errInvalidInterval = errors.New("400 - Invalid interval (expected day or week)")
+	errTooManyBuckets  = errors.New("400 - Too many intervals in time range")
+)
+
+type activityBucket struct {
+	From     time.Time  `json:"from"`
+	CountTxs int        `json:"count_txs"`
+	Received bignum.Int `json:"received"`
+	Sent     bignum.Int `json:"sent"`
+}
+
+type addressActivity struct {
+	Interval   string            `json:"interval"`
+	Buckets    []*activityBucket `json:"buckets"`
+	Incomplete bool              `json:"incomplete,omitempty"` // history is longer than scan limit, counters are partial
+}
+
+// getTime returns time passed as unix-timestamp or date (YYYY-MM-DD) in UTC
+func (c *Context) getTime(name string, defaultValue time.Time) time.Time {
+	s := c.getStr(name, "")
+	if s == "" {
+		return defaultValue
+	}
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(ts, 0).UTC()
+	}
+	t, err := time.Parse("2006-01-02", s)
+	c.assert(err)
+	return t
+}
+
+// addressActivity handles /address/<address>/activity?from=<time>&to=<time>&interval=day|week.
+// Empty intervals are returned explicitly.
+func (c *Context) addressActivity(addr []byte, memo uint64) *addressActivity {
+	res := &addressActivity{Interval: c.getStr("interval", "day")}
+	var step time.Duration
+	switch res.Interval {
+	case "day":
+		step = 24 * time.Hour
+	case "week":
+		step = 7 * 24 * time.Hour
+	default:
+		c.assert(errInvalidInterval)
+	}
+	to := c.getTime("to", time.Now().UTC())
+	from := c.getTime("from", to.Add(-30*24*time.Hour)).Truncate(step)
+	if to.Before(from) || to.Sub(from)/step >= maxActivityBuckets {
+		c.assert(errTooManyBuckets)
+	}
+	for t := from; !t.After(to); t = t.Add(step) {
+		res.Buckets = append(res.Buckets, &activityBucket{From: t, Received: bignum.NewInt(0), Sent: bignum.NewInt(0)})
+	}
+
+	// scan history from the newest transactions back to the beginning of time range
+	complete := c.scanAddressTxs(assets.MDC, addr, memo, true, maxScanTxs, func(tx *chain.Transaction) bool {
+		t := blockTime(tx.BlockTs)
+		if t.Before(from) {
+			return false
+		}
+		if t.After(to) {
+			return true
+		}
+		b := res.Buckets[t.Sub(from)/step]
+		in, out := txTransferAmounts(tx, assets.MDC, addr, memo)
+		b.CountTxs++
+		b.Received, b.Sent = b.Received.Add(in), b.Sent.Add(out)
+		return true
+	})
+	res.Incomplete = !complete
+	return res
+}
@@ -0,0 +1,52 @@
+package restsrv
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	addressPrefix = "MDC"
+	base58Chars   = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	minAddressLen = 24                // decoded length of address without memo (version, address, checksum)
+	maxAddressLen = minAddressLen + 9 // decoded length of address with memo
+)
+
+var errAddressChecksum = errors.New("400 - Invalid address: address checksum mismatch")
+
+// addressByStr returns address by string (MDC<base58> | @<nick> | 0x<userID:hex>).
+// An invalid MDC-address gets specific error: invalid character, wrong length or checksum mismatch.
+func (c *Context) addressByStr(s string) (addr []byte, memo uint64, err error) {
+	addr, memo, err = c.bc.AddressByStr(s)
+	if err != nil && strings.HasPrefix(s, addressPrefix) {
+		err = addressDecodeError(s)
+	}
+	return
+}
+
+// addressDecodeError returns specific error of MDC-address which can't be decoded
+func addressDecodeError(s string) error {
+	body := strings.TrimPrefix(s, addressPrefix)
+	n := new(big.Int)
+	for i, ch := range body {
+		d := strings.IndexRune(base58Chars, ch)
+		if d < 0 {
+			return fmt.Errorf("400 - Invalid address: illegal character %q at position %d (base58 expected)", ch, len(addressPrefix)+i+1)
+		}
+		n.Mul(n, big.NewInt(58)).Add(n, big.NewInt(int64(d)))
+	}
+	size := len(n.Bytes())
+	for i := 0; i < len(body) && body[i] == '1'; i++ { // leading zero bytes
+		size++
+	}
+	if size < minAddressLen {
+		return fmt.Errorf("400 - Invalid address length: %d bytes (address is truncated?)", size)
+	}
+	if size > maxAddressLen {
+		return fmt.Errorf("400 - Invalid address length: %d bytes", size)
+	}
+	return errAddressChecksum
+}
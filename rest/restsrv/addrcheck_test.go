@@ -0,0 +1,34 @@
+package restsrv
+
+import (
+	"testing"
+
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAddress = "MDC6ZKGnnz4g2y8eRoZKhaPjPbjsUGCUrUC"
+
+func TestAddressDecodeError_checksum(t *testing.T) {
+	s := "MDC6ZKGnnz4g2y8eRoZKhaPjPbjsUGCUrUD" // last character is flipped
+
+	_, _, err0 := crypto.DecodeAddress(testAddress)
+	_, _, err1 := crypto.DecodeAddress(s)
+
+	assert.NoError(t, err0)
+	assert.Error(t, err1)
+	assert.Equal(t, errAddressChecksum, addressDecodeError(s))
+	assert.Contains(t, addressDecodeError(s).Error(), "checksum mismatch")
+}
+
+func TestAddressDecodeError_charset(t *testing.T) {
+	err := addressDecodeError("MDC6ZKGnnz4g2y8eRoZKhaPjPbjsUGCUr0C")
+
+	assert.EqualError(t, err, `400 - Invalid address: illegal character '0' at position 34 (base58 expected)`)
+}
+
+func TestAddressDecodeError_length(t *testing.T) {
+	err := addressDecodeError(testAddress[:len(testAddress)-5])
+
+	assert.EqualError(t, err, "400 - Invalid address length: 20 bytes (address is truncated?)")
+}
@@ -0,0 +1,603 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+)
+
+// addressEncodings is address in all supported display forms
+type addressEncodings struct {
+	Address string `json:"address"`           // MDC<base58>
+	Hex     string `json:"hex"`               // 0x<raw address in hex>
+	Nick    string `json:"nick,omitempty"`    // @<nick> if address is registered as user
+	UserID  string `json:"user_id,omitempty"` // 0x<userID:hex> if address is registered as user
+}
+
+func (c *Context) addressEncodings(addr []byte, memo uint64) *addressEncodings {
+	enc := &addressEncodings{
+		Address: crypto.EncodeAddress(addr, memo),
+		Hex:     "0x" + hex.EncodeToString(addr),
+	}
+	user, err := c.userByAddress(addr)
+	c.assert(err)
+	if user != nil {
+		enc.Nick = "@" + user.Nick
+		enc.UserID = "0x" + user.PublicKey().HexID()
+	}
+	return enc
+}
+
+// withEncodings returns true if client asks to include all address encodings into response
+func (c *Context) withEncodings() bool {
+	return c.exists("encodings")
+}
+
+const (
+	maxPortfolioSize = 50   // max count of addresses in /portfolio request
+	maxScanTxs       = 1000 // max count of transactions scanned per address by aggregating requests
+)
+
+// scanAddressTxs calls fn for transactions of address in chronological (or reverse if desc) order until fn returns false.
+// It returns false if history is longer than maxTxs and was not scanned completely.
+func (c *Context) scanAddressTxs(asset, addr []byte, memo uint64, desc bool, maxTxs int, fn func(tx *chain.Transaction) bool) (complete bool) {
+	const pageSize = 100
+	var offset uint64
+	for n := 0; n < maxTxs; {
+		txs, next, err := c.transactionsByAddr(asset, addr, memo, offset, pageSize, desc)
+		c.assert(err)
+		for _, tx := range txs {
+			if n++; n > maxTxs {
+				return false
+			}
+			if !fn(tx) {
+				return true
+			}
+		}
+		if len(txs) < pageSize || next == 0 {
+			return true
+		}
+		offset = next
+	}
+	return false
+}
+
+// scanAddressTxsFrom calls fn for transactions of address from offset until fn returns false or maxTxs are scanned.
+// It returns offset of the first transaction which is not passed to fn or follows the one fn stopped at
+// (0 if history is scanned to the end).
+func (c *Context) scanAddressTxsFrom(asset, addr []byte, memo uint64, offset uint64, desc bool, maxTxs int, fn func(tx *chain.Transaction) bool) (next uint64) {
+	const pageSize = 100
+	for n := 0; ; {
+		txs, pageNext, err := c.transactionsByAddr(asset, addr, memo, offset, pageSize, desc)
+		c.assert(err)
+		for i, tx := range txs {
+			if n++; n > maxTxs {
+				return c.offsetAfter(asset, addr, memo, offset, i, desc)
+			}
+			if !fn(tx) {
+				if i+1 < len(txs) {
+					return c.offsetAfter(asset, addr, memo, offset, i+1, desc)
+				}
+				if len(txs) < pageSize {
+					return 0
+				}
+				return pageNext
+			}
+		}
+		if len(txs) < pageSize || pageNext == 0 {
+			return 0
+		}
+		if offset = pageNext; n >= maxTxs { // scan is cut unless history ends here
+			if c.hasAddressTxs(asset, addr, memo, offset, desc) {
+				return offset
+			}
+			return 0
+		}
+	}
+}
+
+// offsetAfter returns offset of transaction following the first n transactions of address from offset
+func (c *Context) offsetAfter(asset, addr []byte, memo uint64, offset uint64, n int, desc bool) uint64 {
+	if n == 0 {
+		return offset
+	}
+	_, next, err := c.transactionsByAddr(asset, addr, memo, offset, int64(n), desc)
+	c.assert(err)
+	return next
+}
+
+// hasAddressTxs returns true if address has transactions from offset
+func (c *Context) hasAddressTxs(asset, addr []byte, memo uint64, offset uint64, desc bool) bool {
+	txs, _, err := c.transactionsByAddr(asset, addr, memo, offset, 1, desc)
+	c.assert(err)
+	return len(txs) > 0
+}
+
+// balanceAt returns balance of address as of block height (computed back from actual balance by later transactions and fees)
+func (c *Context) balanceAt(asset, addr []byte, memo uint64, height uint64) bignum.Int {
+	info, err := c.bc.AddressInfo(addr, memo, asset)
+	c.assert(err)
+	balance := info.Balance
+	complete := c.scanAddressTxs(asset, addr, memo, true, maxScanTxs, func(tx *chain.Transaction) bool {
+		if tx.BlockNum <= height {
+			return false
+		}
+		in, out := txBalanceChange(c.bc.Cfg, tx, asset, addr, memo)
+		balance = balance.Sub(in).Add(out)
+		return true
+	})
+	if !complete {
+		c.assert(fmt.Errorf("400 - Too many transactions since height %d (max %d)", height, maxScanTxs))
+	}
+	return balance
+}
+
+type assetBalance struct {
+	Asset    string     `json:"asset"`
+	Symbol   string     `json:"symbol"`
+	Decimals int        `json:"decimals"`
+	Balance  bignum.Int `json:"balance"`
+}
+
+// balances handles /address/<address>/balances?offset=<num>&limit=<num>.
+// It returns assets with nonzero balance of address (all assets issued in blockchain are checked).
+func (c *Context) balances(addr []byte, memo uint64) *Response {
+	var list [][]byte
+	for _, asset := range c.knownAssets() {
+		info, err := c.bc.AddressInfo(addr, memo, asset)
+		c.assert(err)
+		if !info.Balance.IsZero() {
+			list = append(list, asset)
+		}
+	}
+	from, to, next := c.pageBounds(len(list))
+	res := []*assetBalance{}
+	for _, asset := range list[from:to] {
+		info, err := c.bc.AddressInfo(addr, memo, asset)
+		c.assert(err)
+		b := &assetBalance{Asset: hex.EncodeToString(asset), Balance: info.Balance}
+		b.Symbol, b.Decimals = c.assetInfo(asset)
+		res = append(res, b)
+	}
+	return pageResponse(res, next).setTotal(uint64(len(list)))
+}
+
+const maxBalanceHeights = 100 // max count of heights in /balances-at request
+
+type balanceAtHeight struct {
+	Height  uint64     `json:"height"`
+	Balance bignum.Int `json:"balance"`
+}
+
+// balancesAt handles /address/<address>/balances-at?heights=<num>,<num>,...&asset=<asset>.
+// History of address is iterated once (from the newest transactions) with snapshots of balance at the requested heights.
+func (c *Context) balancesAt(addr []byte, memo uint64) []*balanceAtHeight {
+	asset := c.getAsset("asset")
+	var heights []uint64
+	for _, s := range strings.Split(c.getStr("heights", ""), ",") {
+		h, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+		c.assert(err)
+		heights = append(heights, h)
+	}
+	if len(heights) > maxBalanceHeights {
+		c.assert(fmt.Errorf("400 - Too many heights (max %d)", maxBalanceHeights))
+	}
+	info, err := c.bc.AddressInfo(addr, memo, asset)
+	c.assert(err)
+	r := newBalanceRollback(info.Balance, heights)
+	complete := c.scanAddressTxs(asset, addr, memo, true, maxScanTxs, func(tx *chain.Transaction) bool {
+		in, out := txBalanceChange(c.bc.Cfg, tx, asset, addr, memo)
+		return r.undo(tx.BlockNum, in, out)
+	})
+	if !complete {
+		c.assert(fmt.Errorf("400 - Address history is too long (max %d transactions)", maxScanTxs))
+	}
+	return r.result()
+}
+
+// balanceRollback walks balance back from actual one by changes of transactions (from the newest)
+// and takes snapshots of balance at heights
+type balanceRollback struct {
+	balance bignum.Int
+	heights []uint64
+	res     []*balanceAtHeight
+	order   []int // indexes of heights from the highest
+	next    int
+}
+
+func newBalanceRollback(balance bignum.Int, heights []uint64) *balanceRollback {
+	r := &balanceRollback{
+		balance: balance,
+		heights: heights,
+		res:     make([]*balanceAtHeight, len(heights)),
+		order:   make([]int, len(heights)),
+	}
+	for i, h := range heights {
+		r.res[i], r.order[i] = &balanceAtHeight{Height: h}, i
+	}
+	sort.Slice(r.order, func(i, j int) bool { return heights[r.order[i]] > heights[r.order[j]] })
+	return r
+}
+
+// undo rolls back change of balance by transaction of block blockNum.
+// It returns false when balances at all heights are known.
+func (r *balanceRollback) undo(blockNum uint64, in, out bignum.Int) bool {
+	for ; r.next < len(r.order) && r.heights[r.order[r.next]] >= blockNum; r.next++ {
+		r.res[r.order[r.next]].Balance = r.balance
+	}
+	if r.next == len(r.order) {
+		return false
+	}
+	r.balance = r.balance.Sub(in).Add(out)
+	return true
+}
+
+// result returns balances at heights (heights before the oldest transaction have balance before it)
+func (r *balanceRollback) result() []*balanceAtHeight {
+	for ; r.next < len(r.order); r.next++ {
+		r.res[r.order[r.next]].Balance = r.balance
+	}
+	return r.res
+}
+
+// confirmedHeight returns height of blocks with at least minConfirmations confirmations
+func (c *Context) confirmedHeight(minConfirmations uint64) uint64 {
+	tip := c.lastBlockNum()
+	if minConfirmations <= 1 {
+		return tip
+	}
+	if minConfirmations-1 > tip {
+		return 0
+	}
+	return tip - minConfirmations + 1
+}
+
+type addressBalance struct {
+	Address          string  `json:"address"`
+	Asset            string  `json:"asset"`
+	Balance          string  `json:"balance"`                     // decimal
+	ConfirmedHeight  *uint64 `json:"confirmed_height,omitempty"`  // if param "min_confirmations" is given
+	ConfirmedBalance string  `json:"confirmed_balance,omitempty"` // balance as of confirmed height
+}
+
+// balance handles /balance?address=<address>&asset=<asset>[&min_confirmations=<num>].
+// Unknown address has zero balance.
+func (c *Context) balance() *addressBalance {
+	addr, memo := c.getAddress("")
+	asset := c.getAsset("asset")
+	info, err := c.bc.AddressInfo(addr, memo, asset)
+	c.assert(err)
+	balance := bignum.NewInt(0)
+	if info != nil {
+		balance = info.Balance
+	}
+	res := &addressBalance{
+		Address: crypto.EncodeAddress(addr, memo),
+		Asset:   hex.EncodeToString(asset),
+		Balance: balance.String(),
+	}
+	if c.exists("min_confirmations") {
+		height := c.confirmedHeight(c.getUint("min_confirmations"))
+		res.ConfirmedHeight = &height
+		res.ConfirmedBalance = c.balanceAt(asset, addr, memo, height).String()
+	}
+	return res
+}
+
+type portfolioAsset struct {
+	Asset    string     `json:"asset"`
+	Balance  bignum.Int `json:"balance"`
+	Received bignum.Int `json:"received"`
+	Sent     bignum.Int `json:"sent"`
+}
+
+type portfolioAddress struct {
+	Address    string            `json:"address"`
+	CountTxs   int               `json:"count_txs"`
+	Assets     []*portfolioAsset `json:"assets"`
+	Incomplete bool              `json:"incomplete,omitempty"` // history is longer than scan limit, counters are partial
+}
+
+type portfolio struct {
+	CountTxs  int                 `json:"count_txs"`
+	Totals    []*portfolioAsset   `json:"totals"`
+	Addresses []*portfolioAddress `json:"addresses"`
+}
+
+// portfolio handles /portfolio?addresses=<address>,<address>,...
+// Assets of address are assets issued in blockchain which address has balance or history of.
+func (c *Context) portfolio() *portfolio {
+	strAddrs := strings.Split(c.getStr("addresses", ""), ",")
+	if len(strAddrs) > maxPortfolioSize {
+		c.assert(fmt.Errorf("400 - Too many addresses (max %d)", maxPortfolioSize))
+	}
+	assetList := c.knownAssets()
+	res := &portfolio{Totals: []*portfolioAsset{}}
+	totals := make([]*portfolioAsset, len(assetList))
+	for _, s := range strAddrs {
+		addr, memo, err := c.addressByStr(strings.TrimSpace(s))
+		c.assert(err)
+		pa := &portfolioAddress{Address: crypto.EncodeAddress(addr, memo), Assets: []*portfolioAsset{}}
+		seen := map[string]bool{} // transactions of address (counted once for all assets)
+		for i, asset := range assetList {
+			info, err := c.bc.AddressInfo(addr, memo, asset)
+			c.assert(err)
+			a := newPortfolioAsset(asset)
+			a.Balance = info.Balance
+			hasTxs := false
+			complete := c.scanAddressTxs(asset, addr, memo, false, maxScanTxs, func(tx *chain.Transaction) bool {
+				in, out := txTransferAmounts(tx, asset, addr, memo)
+				a.Received, a.Sent = a.Received.Add(in), a.Sent.Add(out)
+				if h := string(tx.Hash()); !seen[h] {
+					seen[h] = true
+					pa.CountTxs++
+				}
+				hasTxs = true
+				return true
+			})
+			if !hasTxs && a.Balance.IsZero() {
+				continue
+			}
+			pa.Incomplete = pa.Incomplete || !complete
+			pa.Assets = append(pa.Assets, a)
+
+			if totals[i] == nil {
+				totals[i] = newPortfolioAsset(asset)
+			}
+			t := totals[i]
+			t.Balance, t.Received, t.Sent = t.Balance.Add(a.Balance), t.Received.Add(a.Received), t.Sent.Add(a.Sent)
+		}
+		res.CountTxs += pa.CountTxs
+		res.Addresses = append(res.Addresses, pa)
+	}
+	for _, t := range totals {
+		if t != nil {
+			res.Totals = append(res.Totals, t)
+		}
+	}
+	return res
+}
+
+func newPortfolioAsset(asset []byte) *portfolioAsset {
+	return &portfolioAsset{
+		Asset:    hex.EncodeToString(asset),
+		Balance:  bignum.NewInt(0),
+		Received: bignum.NewInt(0),
+		Sent:     bignum.NewInt(0),
+	}
+}
+
+type paymentURI struct {
+	URI     string     `json:"uri"`
+	Address string     `json:"address"` // canonical address (with memo)
+	Memo    uint64     `json:"memo,omitempty"`
+	Amount  bignum.Int `json:"amount"`
+	Asset   string     `json:"asset,omitempty"`
+}
+
+// paymentURI handles /address/<address>/payment-uri?amount=<num>&memo=<num|hex>&asset=<asset>.
+// Result is payment request URI "mediacoin:<address>?amount=<num>&memo=<num>" for rendering as QR-code.
+func (c *Context) paymentURI(addr []byte, memo uint64) *paymentURI {
+	amount := c.getAmount("amount")
+	var asset string
+	if c.exists("asset") {
+		asset, _ = c.assetInfo(c.getAsset("asset"))
+	}
+	q := url.Values{}
+	if !amount.IsZero() {
+		q.Set("amount", amount.String())
+	}
+	if memo != 0 {
+		q.Set("memo", strconv.FormatUint(memo, 10))
+	}
+	if asset != "" {
+		q.Set("asset", asset)
+	}
+	uri := "mediacoin:" + crypto.EncodeAddress(addr, 0)
+	if len(q) > 0 {
+		uri += "?" + q.Encode()
+	}
+	return &paymentURI{
+		URI:     uri,
+		Address: crypto.EncodeAddress(addr, memo),
+		Memo:    memo,
+		Amount:  amount,
+		Asset:   asset,
+	}
+}
+
+// balanceBreakdown contains only components which are tracked by blockchain node.
+// Blockchain has no locked or staked balances, so spendable balance is total balance without pending spends.
+type balanceBreakdown struct {
+	Address   string      `json:"address"`
+	Asset     string      `json:"asset"`
+	Total     bignum.Int  `json:"total"`
+	Spendable bignum.Int  `json:"spendable"`
+	Pending   *bignum.Int `json:"pending,omitempty"` // sent by pending transactions
+}
+
+// balanceBreakdown handles /address/<address>/balance-breakdown?asset=<asset>
+func (c *Context) balanceBreakdown(addr []byte, memo uint64) *balanceBreakdown {
+	asset := c.getAsset("asset")
+	info, err := c.bc.AddressInfo(addr, memo, asset)
+	c.assert(err)
+	res := &balanceBreakdown{
+		Address:   crypto.EncodeAddress(addr, memo),
+		Asset:     hex.EncodeToString(asset),
+		Total:     info.Balance,
+		Spendable: info.Balance,
+	}
+	pending := bignum.NewInt(0)
+	for _, tx := range c.submittedPending() {
+		if tx.Sender != nil && bytes.Equal(tx.Sender.Address(), addr) {
+			_, out := txBalanceChange(c.bc.Cfg, tx, asset, addr, memo)
+			pending = pending.Add(out)
+		}
+	}
+	res.Pending, res.Spendable = &pending, res.Spendable.Sub(pending)
+	return res
+}
+
+// addressDelta is compact change of address state between two heights
+type addressDelta struct {
+	Address       string               `json:"address"`
+	FromHeight    uint64               `json:"from_height"`
+	ToHeight      uint64               `json:"to_height"`
+	FromBalance   bignum.Int           `json:"from_balance"`   // balance at from-height (anchor for reconciliation)
+	BalanceChange bignum.Int           `json:"balance_change"` // balance at to-height minus balance at from-height
+	Txs           []*chain.Transaction `json:"txs"`            // transactions included after from-height, in chronological order
+}
+
+// addressDelta handles /address/<address>/delta?from=<height>
+func (c *Context) addressDelta(addr []byte, memo uint64) *addressDelta {
+	from := c.getUint("from")
+	asset := assets.MDC
+	to := c.lastBlockNum()
+	if from > to {
+		c.assert(errors.New("400 - Invalid from-height"))
+	}
+	info, err := c.bc.AddressInfo(addr, memo, asset)
+	c.assert(err)
+	change := bignum.NewInt(0)
+	var txs []*chain.Transaction
+	complete := c.scanAddressTxs(asset, addr, memo, true, maxScanTxs, func(tx *chain.Transaction) bool {
+		if tx.BlockNum <= from {
+			return false
+		}
+		in, out := txBalanceChange(c.bc.Cfg, tx, asset, addr, memo)
+		change = change.Add(in).Sub(out)
+		txs = append(txs, tx)
+		return true
+	})
+	if !complete {
+		c.assert(fmt.Errorf("400 - Too many transactions since height %d (max %d)", from, maxScanTxs))
+	}
+	for i, j := 0, len(txs)-1; i < j; i, j = i+1, j-1 {
+		txs[i], txs[j] = txs[j], txs[i]
+	}
+	return &addressDelta{
+		Address:       crypto.EncodeAddress(addr, memo),
+		FromHeight:    from,
+		ToHeight:      to,
+		FromBalance:   info.Balance.Sub(change),
+		BalanceChange: change,
+		Txs:           txs,
+	}
+}
+
+type fundingSource struct {
+	Address      string             `json:"address"`
+	FirstSeen    uint64             `json:"first_seen"`    // block of the first transaction of address
+	FirstSeenTs  int64              `json:"first_seen_ts"` // timestamp of the first transaction of address
+	Tx           *chain.Transaction `json:"tx"`            // first incoming transaction
+	Amount       bignum.Int         `json:"amount"`
+	Sender       string             `json:"sender,omitempty"`
+	SenderNick   string             `json:"sender_nick,omitempty"`
+	SenderUserID string             `json:"sender_user_id,omitempty"`
+}
+
+// fundingSource handles /address/<address>/funding-source?asset=<asset>.
+// It returns the earliest incoming transaction of address with its sender.
+func (c *Context) fundingSource(addr []byte, memo uint64) *fundingSource {
+	asset := c.getAsset("asset")
+	var res *fundingSource
+	var first *chain.Transaction
+	c.scanAddressTxs(asset, addr, memo, false, maxScanTxs, func(tx *chain.Transaction) bool {
+		if first == nil {
+			first = tx
+		}
+		if in, _ := txTransferAmounts(tx, asset, addr, memo); in.Sign() > 0 {
+			res = &fundingSource{Tx: tx, Amount: in}
+			return false
+		}
+		return true
+	})
+	c.assertFound(res != nil)
+	res.Address = crypto.EncodeAddress(addr, memo)
+	res.FirstSeen, res.FirstSeenTs = first.BlockNum, first.BlockTs
+	if sender := res.Tx.Sender; sender != nil {
+		res.Sender = sender.StrAddress()
+		user, err := c.userByAddress(sender.Address())
+		c.assert(err)
+		if user != nil {
+			res.SenderNick = "@" + user.Nick
+			res.SenderUserID = "0x" + user.PublicKey().HexID()
+		}
+	}
+	return res
+}
+
+type payee struct {
+	Address     string     `json:"address"`
+	Nick        string     `json:"nick,omitempty"`
+	Total       bignum.Int `json:"total"`
+	CountTxs    int        `json:"count_txs"`
+	LastPayment int64      `json:"last_payment"` // block timestamp of the last payment
+}
+
+type payees struct {
+	Payees     []*payee `json:"payees"`
+	NextOffset uint64   `json:"next_offset,omitempty"`
+	Incomplete bool     `json:"incomplete,omitempty"` // history is longer than scan limit, totals are partial
+}
+
+// payees handles /address/<address>/payees?from=<time>&to=<time>&asset=<asset>&offset=<num>&limit=<num>.
+// Recipients of outgoing transfers are aggregated and sorted by descending total; nicks are resolved for the returned page.
+func (c *Context) payees(addr []byte, memo uint64) *payees {
+	asset := c.getAsset("asset")
+	from := c.getTime("from", time.Time{})
+	to := c.getTime("to", time.Now().UTC())
+	idx := map[string]*payee{}
+	addrs := map[string][]byte{}
+	list := []*payee{}
+	complete := c.scanAddressTxs(asset, addr, memo, true, maxScanTxs, func(tx *chain.Transaction) bool {
+		t := blockTime(tx.BlockTs)
+		if t.Before(from) {
+			return false
+		}
+		tr, ok := tx.TxObject().(*txobj.SimpleTransfer)
+		if !ok || t.After(to) || tx.Sender == nil || !bytes.Equal(tx.Sender.Address(), addr) {
+			return true
+		}
+		for _, out := range tr.Outs {
+			if !bytes.Equal(out.Asset, asset) || bytes.Equal(out.To, addr) {
+				continue
+			}
+			key := crypto.EncodeAddress(out.To, out.ToMemo)
+			p := idx[key]
+			if p == nil {
+				p = &payee{Address: key, Total: bignum.NewInt(0), LastPayment: tx.BlockTs}
+				idx[key] = p
+				addrs[key] = out.To
+				list = append(list, p)
+			}
+			p.Total = p.Total.Add(out.Amount)
+			p.CountTxs++
+		}
+		return true
+	})
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Total.Cmp(list[j].Total) > 0 })
+	i, j, next := c.pageBounds(len(list))
+	res := &payees{Payees: list[i:j], Incomplete: !complete}
+	for _, p := range res.Payees { // every lookup scans history of payee, so only payees of the page are resolved
+		if user, err := c.userByAddress(addrs[p.Address]); err == nil && user != nil {
+			p.Nick = "@" + user.Nick
+		}
+	}
+	if next != 0 {
+		res.NextOffset = next
+	}
+	return res
+}
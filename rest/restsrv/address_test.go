@@ -0,0 +1,92 @@
+package restsrv
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTransferOf(asset []byte, to string, amount int64) *txobj.SimpleTransfer {
+	return &txobj.SimpleTransfer{Outs: []*txobj.TransferOutput{{Asset: asset, Amount: bignum.NewInt(amount), To: []byte(to)}}}
+}
+
+func TestBalanceChange_SenderPaysFee(t *testing.T) {
+	in, out := balanceChange([]byte("A"), bignum.NewInt(3), testTransferOf(feeAsset, "B", 50), feeAsset, []byte("A"), 0)
+
+	assert.Equal(t, "0", in.String())
+	assert.Equal(t, "53", out.String())
+}
+
+func TestBalanceChange_RecipientDoesntPayFee(t *testing.T) {
+	in, out := balanceChange([]byte("A"), bignum.NewInt(3), testTransferOf(feeAsset, "B", 50), feeAsset, []byte("B"), 0)
+
+	assert.Equal(t, "50", in.String())
+	assert.Equal(t, "0", out.String())
+}
+
+func TestBalanceChange_FeeOfOtherAsset(t *testing.T) {
+	token := []byte{0x0a}
+
+	_, outToken := balanceChange([]byte("A"), bignum.NewInt(3), testTransferOf(token, "B", 50), token, []byte("A"), 0)
+	_, outFee := balanceChange([]byte("A"), bignum.NewInt(3), testTransferOf(token, "B", 50), feeAsset, []byte("A"), 0)
+
+	assert.Equal(t, "50", outToken.String())
+	assert.Equal(t, "3", outFee.String())
+}
+
+func TestBalanceChange_FeeOfTxWithoutTransfer(t *testing.T) {
+	_, out := balanceChange([]byte("A"), bignum.NewInt(3), nil, feeAsset, []byte("A"), 0) // e.g. registration of user
+
+	assert.Equal(t, "3", out.String())
+}
+
+func TestBalanceRollback_Fees(t *testing.T) {
+	r := newBalanceRollback(bignum.NewInt(100), []uint64{5, 15, 25})
+
+	// history of address A from the newest: block 20 - sent 30 with fee 2, block 10 - received 50
+	in, out := balanceChange([]byte("A"), bignum.NewInt(2), testTransferOf(feeAsset, "B", 30), feeAsset, []byte("A"), 0)
+	more1 := r.undo(20, in, out)
+	in, out = balanceChange([]byte("C"), bignum.NewInt(1), testTransferOf(feeAsset, "A", 50), feeAsset, []byte("A"), 0)
+	more2 := r.undo(10, in, out)
+	res := r.result()
+
+	assert.True(t, more1)
+	assert.True(t, more2)
+	assert.Equal(t, "100", res[2].Balance.String()) // height 25
+	assert.Equal(t, "132", res[1].Balance.String()) // height 15: before transfer of 30 + fee 2
+	assert.Equal(t, "82", res[0].Balance.String())  // height 5: before receiving 50
+}
+
+func TestContext_userByAddress_NotRegistered(t *testing.T) {
+	c, _ := newTestContext(httptest.NewRequest("GET", "/address/x/name", nil))
+	c.bc = newTestChain(t)
+	addr := crypto.NewPrivateKeyBySecret("alice").PublicKey().Address()
+
+	user, err := c.userByAddress(addr)
+
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+	assert.Contains(t, c.users, string(addr)) // absent user is cached for request too
+}
+
+func TestContext_paymentURI_Asset(t *testing.T) {
+	c, _ := newTestAssetsContext(t, "/address/x/payment-uri?amount=5&asset=0a0b0c")
+	addr := crypto.NewPrivateKeyBySecret("a").PublicKey().Address()
+
+	res := c.paymentURI(addr, 0)
+
+	assert.Equal(t, "USD", res.Asset)
+	assert.Contains(t, res.URI, "asset=USD")
+}
+
+func TestContext_paymentURI_UnknownAsset(t *testing.T) {
+	c, rw := newTestAssetsContext(t, "/address/x/payment-uri?asset=XYZ")
+	addr := crypto.NewPrivateKeyBySecret("a").PublicKey().Address()
+
+	assert.Panics(t, func() { c.paymentURI(addr, 0) })
+	assert.Equal(t, 400, rw.Code)
+}
@@ -0,0 +1,46 @@
+package restsrv
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/mediacoin-pro/core/common/xlog"
+)
+
+var (
+	errAdminDisabled = errors.New("403 - Admin API is disabled")
+	errUnauthorized  = errors.New("401 - Invalid API key")
+	errNoConfigFile  = errors.New("400 - Config file is not set")
+)
+
+// assertAdmin aborts request if it is not authorized by admin API key (header X-API-Key or "Authorization: Bearer <key>")
+func (c *Context) assertAdmin() {
+	if c.cfg.AdminKey == "" {
+		c.abort(errAdminDisabled, http.StatusForbidden)
+	}
+	if subtle.ConstantTimeCompare([]byte(c.apiKey()), []byte(c.cfg.AdminKey)) != 1 {
+		c.abort(errUnauthorized, http.StatusUnauthorized)
+	}
+}
+
+// adminReload handles /admin/reload. It re-reads mutable options from config file and applies them atomically.
+func (c *Context) adminReload() interface{} {
+	if c.cfg.ConfigFile == "" {
+		c.assert(errNoConfigFile)
+	}
+	cfg, restartRequired, err := c.cfg.reload()
+	c.assert(err)
+	if r := c.applyRateLimits(c.cfg, cfg); len(r) > 0 {
+		restartRequired = append(restartRequired, r...)
+		sort.Strings(restartRequired)
+	}
+	c.setConfig(cfg)
+	xlog.Info.Printf("rest> [%s] config reloaded from %s by %s (restart required: %v)", c.reqID, cfg.ConfigFile, c.req.RemoteAddr, restartRequired)
+
+	return struct {
+		Config          *Config  `json:"config"`
+		RestartRequired []string `json:"restart_required"`
+	}{cfg, restartRequired}
+}
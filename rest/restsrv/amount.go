@@ -0,0 +1,76 @@
+package restsrv
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/common/bignum"
+)
+
+var (
+	errInvalidAmount  = errors.New("400 - Invalid amount: decimal number expected")
+	errNegativeAmount = errors.New("400 - Negative amount")
+)
+
+// coinDecimals is count of decimal places of coin (assets.Coin = 10^coinDecimals base units)
+var coinDecimals = len(strconv.FormatInt(assets.Coin, 10)) - 1
+
+// parseAmount parses non-negative decimal number with up to decimals fractional digits
+// as integer count of 10^-decimals units. Values are not limited by int64/uint64 range.
+func parseAmount(s string, decimals int) (n bignum.Int, err error) {
+	if strings.HasPrefix(s, "-") {
+		return n, errNegativeAmount
+	}
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+		if frac == "" {
+			return n, errInvalidAmount
+		}
+	}
+	if intPart == "" || !isDigits(intPart) || !isDigits(frac) {
+		return n, errInvalidAmount
+	}
+	if len(frac) > decimals {
+		return n, fmt.Errorf("400 - Amount has more than %d decimal places", decimals)
+	}
+	v, ok := new(big.Int).SetString(intPart+frac+strings.Repeat("0", decimals-len(frac)), 10)
+	if !ok {
+		return n, errInvalidAmount
+	}
+	return bignum.NewFromBig(v), nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// getAmount returns amount in base units of asset given by param name.
+// With param amount_unit=coin amount is decimal number of coins (e.g. "1.5"), otherwise it's integer count of base units.
+func (c *Context) getAmount(name string) bignum.Int {
+	n, err := parseAmount(strings.TrimSpace(c.getStr(name, "0")), c.amountDecimals())
+	c.assert(err)
+	return n
+}
+
+// amountDecimals returns count of decimal places of amounts by param amount_unit
+func (c *Context) amountDecimals() int {
+	switch unit := c.getStr("amount_unit", "base"); unit {
+	case "base":
+		return 0
+	case "coin":
+		return coinDecimals
+	default:
+		c.assert(errors.New("400 - Unknown amount unit (expected base or coin)"))
+		return 0
+	}
+}
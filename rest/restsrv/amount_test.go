@@ -0,0 +1,72 @@
+package restsrv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount_Integer(t *testing.T) {
+	n, err := parseAmount("1500000", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1500000", n.String())
+}
+
+func TestParseAmount_Decimal(t *testing.T) {
+	n1, err1 := parseAmount("1.5", 6)
+	n2, err2 := parseAmount("0.000001", 6)
+	n3, err3 := parseAmount("2", 6)
+
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Equal(t, "1500000", n1.String())
+	assert.Equal(t, "1", n2.String())
+	assert.Equal(t, "2000000", n3.String())
+}
+
+func TestParseAmount_NearInt64Limit(t *testing.T) {
+	n1, err1 := parseAmount("9223372036854775807", 0) // 2^63-1
+	n2, err2 := parseAmount("9223372036854775808", 0) // 2^63
+	n3, err3 := parseAmount("9223372036854.775808", 6)
+
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Equal(t, "9223372036854775807", n1.String())
+	assert.Equal(t, "9223372036854775808", n2.String())
+	assert.Equal(t, "9223372036854775808", n3.String())
+}
+
+func TestParseAmount_AboveUint64(t *testing.T) {
+	n, err := parseAmount("18446744073709551616000", 0) // 2^64 * 1000
+
+	assert.NoError(t, err)
+	assert.Equal(t, "18446744073709551616000", n.String())
+}
+
+func TestParseAmount_Negative(t *testing.T) {
+	_, err1 := parseAmount("-1", 0)
+	_, err2 := parseAmount("-0.5", 6)
+
+	assert.Equal(t, errNegativeAmount, err1)
+	assert.Equal(t, errNegativeAmount, err2)
+}
+
+func TestParseAmount_Malformed(t *testing.T) {
+	for _, s := range []string{"", "abc", "1.", ".5", "1.2.3", "1e6", "+1", " 1", "0x10", "1,5"} {
+		_, err := parseAmount(s, 6)
+
+		assert.Equal(t, errInvalidAmount, err, s)
+	}
+}
+
+func TestParseAmount_Precision(t *testing.T) {
+	_, err1 := parseAmount("1.5", 0)
+	_, err2 := parseAmount("1.0000001", 6)
+
+	assert.EqualError(t, err1, "400 - Amount has more than 0 decimal places")
+	assert.EqualError(t, err2, "400 - Amount has more than 6 decimal places")
+	assert.Equal(t, 400, statusForError(err2))
+}
@@ -0,0 +1,71 @@
+package restsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestAssetsContext returns test context of blockchain with issued assets 0a0b and 0a0b0c (symbol USD)
+func newTestAssetsContext(t *testing.T, url string) (*Context, *httptest.ResponseRecorder) {
+	c, rw := newTestContext(httptest.NewRequest("GET", url, nil))
+	c.index = newChainIndex(newTestChain(t))
+	c.index.assets = append(c.index.assets, []byte{0x0a, 0x0b}, []byte{0x0a, 0x0b, 0x0c})
+	c.AssetSymbols = map[string][]byte{"USD": {0x0a, 0x0b, 0x0c}}
+	return c, rw
+}
+
+func TestContext_getAsset(t *testing.T) {
+	for url, exp := range map[string][]byte{
+		"/txs":              assets.MDC,
+		"/txs?asset=mdc":    assets.MDC,
+		"/txs?asset=usd":    {0x0a, 0x0b, 0x0c},
+		"/txs?asset=0x0a0b": {0x0a, 0x0b},
+		"/txs?asset=0a0b0c": {0x0a, 0x0b, 0x0c},
+	} {
+		c, _ := newTestAssetsContext(t, url)
+
+		assert.Equal(t, exp, c.getAsset("asset"), url)
+	}
+}
+
+func TestContext_getAsset_Malformed(t *testing.T) {
+	for _, url := range []string{"/txs?asset=BTC", "/txs?asset=0x", "/txs?asset=0xabc", "/txs?asset=0x0c"} {
+		c, rw := newTestAssetsContext(t, url)
+
+		func() {
+			defer func() { recover() }()
+			c.getAsset("asset")
+		}()
+
+		assert.Equal(t, http.StatusBadRequest, rw.Code, url)
+	}
+}
+
+func TestContext_assetInfo(t *testing.T) {
+	c, _ := newTestAssetsContext(t, "/address/x/balances")
+
+	symbol, decimals := c.assetInfo([]byte{0x0a, 0x0b, 0x0c})
+	assert.Equal(t, "USD", symbol)
+	assert.Equal(t, coinDecimals, decimals)
+
+	symbol, decimals = c.assetInfo([]byte{0x0a, 0x0b})
+	assert.Equal(t, "0x0a0b", symbol)
+	assert.Equal(t, coinDecimals, decimals)
+}
+
+func TestContext_managedAssets(t *testing.T) {
+	c, _ := newTestAssetsContext(t, "/address/x/managed-assets")
+	issuer := crypto.NewPrivateKeyBySecret("issuer")
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().Address()
+	c.index.issues[string([]byte{0x0a, 0x0b})] = txobj.NewSimpleTransfer(c.bc, issuer, []byte{0x0a, 0x0b}, bignum.NewInt(100), 0, to, 0, "", 1)
+
+	assert.Equal(t, []*managedAsset{{Asset: "0a0b", Permissions: []string{"issuer"}}}, c.managedAssets(issuer.PublicKey().Address()))
+	assert.Equal(t, []*managedAsset{}, c.managedAssets(to))
+}
@@ -0,0 +1,46 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/mediacoin-pro/core/chain"
+)
+
+// blockByHash returns block by hash (nil if block is unknown)
+func (c *Context) blockByHash(hash []byte) (*chain.Block, error) {
+	num, ok, err := c.index.blockNum(hash)
+	if err != nil || !ok {
+		return nil, err
+	}
+	block, err := c.bc.GetBlock(num)
+	if err != nil || block == nil || !bytes.Equal(block.Hash(), hash) {
+		return nil, err
+	}
+	return block, nil
+}
+
+// blockView returns block as response object. JSON-object of block has field "hash" (is added if block encoding lacks it).
+func (c *Context) blockView(block *chain.Block) interface{} {
+	if block == nil || c.wantsBinary() || c.wantsProtobuf() {
+		return block
+	}
+	data, err := json.Marshal(block)
+	if err != nil || !bytes.HasPrefix(data, []byte("{")) {
+		return block
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return block
+	}
+	if _, ok := fields["hash"]; ok {
+		return block
+	}
+	hash, _ := json.Marshal(hex.EncodeToString(block.Hash()))
+	res := append([]byte(`{"hash":`), hash...)
+	if len(fields) > 0 {
+		res = append(res, ',')
+	}
+	return json.RawMessage(append(res, data[1:]...))
+}
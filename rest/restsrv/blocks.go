@@ -0,0 +1,306 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/common/bin"
+)
+
+// scanBlocks calls fn for blocks [from, to] in ascending order
+func (c *Context) scanBlocks(from, to uint64, fn func(block *chain.Block)) {
+	const pageSize = 100
+	for from <= to {
+		blocks, err := c.getBlocks(from, pageSize, false)
+		c.assert(err)
+		if len(blocks) == 0 {
+			return
+		}
+		for _, b := range blocks {
+			if b.Num > to {
+				return
+			}
+			fn(b)
+		}
+		from = blocks[len(blocks)-1].Num + 1
+	}
+}
+
+// blocksCount returns count of blocks of blockchain
+func (c *Context) blocksCount() uint64 {
+	if last := c.bc.LastBlock(); last != nil {
+		return last.Num + 1
+	}
+	return 0
+}
+
+// lastBlockNum returns number of the last block of blockchain (0 if blockchain is empty)
+func (c *Context) lastBlockNum() uint64 {
+	if last := c.bc.LastBlock(); last != nil {
+		return last.Num
+	}
+	return 0
+}
+
+// blocksPage returns page of blocks with cursor of the next page (cursor is empty for the last page)
+func (c *Context) blocksPage(blocks []*chain.Block, limit int64, desc bool) *Response {
+	resp := &Response{Results: blocks}
+	if n := len(blocks); int64(n) == limit && n > 0 {
+		if last := blocks[n-1].Num; !desc {
+			resp.NextCursor = c.sealOffsetCursor(last + 1)
+		} else if last > 0 {
+			resp.NextCursor = c.sealOffsetCursor(last - 1)
+		}
+	}
+	return resp
+}
+
+type producerStat struct {
+	Producer string  `json:"producer"`
+	Blocks   int     `json:"blocks"`
+	Share    float64 `json:"share"`
+}
+
+type productionStat struct {
+	Window    uint64          `json:"window"`
+	FromBlock uint64          `json:"from_block"`
+	ToBlock   uint64          `json:"to_block"`
+	Producers []*producerStat `json:"producers"`
+}
+
+// validatorsProduction handles /validators/production?window=<count-blocks>
+func (c *Context) validatorsProduction() *productionStat {
+	window := c.getUint("window")
+	if window == 0 {
+		window = 1000
+	} else if window > c.cfg.MaxBlocksWindow {
+		c.assert(fmt.Errorf("400 - Window is too large (max %d blocks)", c.cfg.MaxBlocksWindow))
+	}
+	last := c.bc.LastBlock()
+	if last == nil {
+		return &productionStat{Producers: []*producerStat{}}
+	}
+	res := &productionStat{ToBlock: last.Num, Producers: []*producerStat{}}
+	if last.Num+1 > window {
+		res.FromBlock = last.Num + 1 - window
+	}
+	res.Window = res.ToBlock - res.FromBlock + 1
+
+	stats := map[string]*producerStat{}
+	c.scanBlocks(res.FromBlock, res.ToBlock, func(b *chain.Block) {
+		var producer string
+		if b.Miner != nil {
+			producer = b.Miner.StrAddress()
+		}
+		st := stats[producer]
+		if st == nil {
+			st = &producerStat{Producer: producer}
+			stats[producer] = st
+			res.Producers = append(res.Producers, st)
+		}
+		st.Blocks++
+	})
+	for _, st := range res.Producers {
+		st.Share = float64(st.Blocks) / float64(res.Window)
+	}
+	sort.Slice(res.Producers, func(i, j int) bool {
+		return res.Producers[i].Blocks > res.Producers[j].Blocks
+	})
+	return res
+}
+
+// getBlock returns block by number or aborts request with 404-response
+func (c *Context) getBlock(num uint64) *chain.Block {
+	block, err := c.bc.GetBlock(num)
+	c.assert(err)
+	c.assertFound(block != nil)
+	return block
+}
+
+// blockTxs returns transactions of block (involving address if param "address" is given)
+func (c *Context) blockTxs(num uint64) []*chain.Transaction {
+	block := c.getBlock(num)
+	if !c.exists("address") {
+		return block.Txs
+	}
+	addr, memo := c.getAddress("")
+	txs := []*chain.Transaction{}
+	for _, tx := range block.Txs {
+		if txInvolves(tx, addr, memo) {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+type truncatedBlock struct {
+	*chain.BlockHeader
+	Hash      string               `json:"hash"`
+	CountTxs  int                  `json:"count_txs"`
+	Txs       []*chain.Transaction `json:"txs"`
+	TxHashes  []string             `json:"tx_hashes,omitempty"` // hashes of omitted transactions
+	Truncated bool                 `json:"truncated"`
+	MoreTxs   string               `json:"more_txs,omitempty"` // request path of all block transactions
+}
+
+// truncatedBlock returns block with first transactions (not more than max_txs and max_bytes of binary-encoded transactions)
+// and hashes of the rest transactions
+func (c *Context) truncatedBlock(block *chain.Block) *truncatedBlock {
+	maxTxs, maxBytes := c.getInt("max_txs"), c.getInt("max_bytes")
+	res := &truncatedBlock{
+		BlockHeader: block.BlockHeader,
+		Hash:        hex.EncodeToString(block.Hash()),
+		CountTxs:    len(block.Txs),
+		Txs:         []*chain.Transaction{},
+	}
+	var size int64
+	for i, tx := range block.Txs {
+		if !res.Truncated {
+			size += txSize(tx)
+			res.Truncated = maxTxs > 0 && int64(i) >= maxTxs || maxBytes > 0 && size > maxBytes
+		}
+		if res.Truncated {
+			res.TxHashes = append(res.TxHashes, hex.EncodeToString(tx.Hash()))
+		} else {
+			res.Txs = append(res.Txs, tx)
+		}
+	}
+	if res.Truncated {
+		res.MoreTxs = fmt.Sprintf("/block/%d/txs", block.Num)
+	}
+	return res
+}
+
+const (
+	defaultSummaryBlocks = 10
+	maxSummaryBlocks     = 100
+	blockSummaryCacheTTL = time.Hour
+)
+
+// blockSummary is compact block info for status views
+type blockSummary struct {
+	Height  uint64 `json:"height"`
+	Hash    string `json:"hash"`
+	Time    int64  `json:"time"` // block timestamp in microseconds
+	TxCount int    `json:"tx_count"`
+}
+
+// blocksSummary handles /blocks/summary?count=<num>.
+// Summaries are cached by block hash, so blocks replaced by reorganization don't get stale summaries.
+func (c *Context) blocksSummary() []*blockSummary {
+	count := c.getUint("count")
+	if count == 0 {
+		count = defaultSummaryBlocks
+	} else if count > maxSummaryBlocks {
+		count = maxSummaryBlocks
+	}
+	res := []*blockSummary{}
+	last := c.bc.LastBlock()
+	if last == nil {
+		return res
+	}
+	for num, n := last.Num, uint64(0); n < count; num, n = num-1, n+1 {
+		res = append(res, c.blockSummary(num))
+		if num == 0 {
+			break
+		}
+	}
+	return res
+}
+
+func (c *Context) blockSummary(num uint64) *blockSummary {
+	block := c.getBlock(num)
+	hash := hex.EncodeToString(block.Hash())
+	key := "block-summary:" + hash
+	s := &blockSummary{}
+	if data, ok := c.Cache.Get(key); ok && bin.Decode(data, s) == nil {
+		return s
+	}
+	s = &blockSummary{
+		Height:  block.Num,
+		Hash:    hash,
+		Time:    block.Timestamp,
+		TxCount: len(block.Txs),
+	}
+	c.Cache.Set(key, bin.Encode(s), blockSummaryCacheTTL)
+	return s
+}
+
+const blockOverviewCacheTTL = 24 * time.Hour
+
+// blockOverview is block header with aggregates of block transactions for explorer block page
+type blockOverview struct {
+	Num             uint64     `json:"num"`
+	Hash            string     `json:"hash"`
+	PrevHash        string     `json:"prev_hash"`
+	Timestamp       int64      `json:"timestamp"`
+	Miner           string     `json:"miner,omitempty"`
+	CountTxs        int        `json:"count_txs"`
+	Volume          bignum.Int `json:"volume"`           // total amount of MDC transferred
+	UniqueAddresses int        `json:"unique_addresses"` // count of distinct senders and recipients
+	TotalFee        bignum.Int `json:"total_fee"`
+	AvgFee          bignum.Int `json:"avg_fee"`
+}
+
+// blockOverview handles /block/<num>/overview. Overview is cached by block hash.
+func (c *Context) blockOverview(num uint64) *blockOverview {
+	block := c.getBlock(num)
+	hash := hex.EncodeToString(block.Hash())
+	key := "block-overview:" + hash
+	res := &blockOverview{}
+	if data, ok := c.Cache.Get(key); ok && bin.Decode(data, res) == nil {
+		return res
+	}
+	res = &blockOverview{
+		Num:       block.Num,
+		Hash:      hash,
+		PrevHash:  hex.EncodeToString(block.PrevHash),
+		Timestamp: block.Timestamp,
+		CountTxs:  len(block.Txs),
+		Volume:    bignum.NewInt(0),
+		TotalFee:  bignum.NewInt(0),
+		AvgFee:    bignum.NewInt(0),
+	}
+	if block.Miner != nil {
+		res.Miner = block.Miner.StrAddress()
+	}
+	addrs := map[string]bool{}
+	for _, tx := range block.Txs {
+		if tx.Sender != nil {
+			addrs[string(tx.Sender.Address())] = true
+		}
+		if tr, ok := tx.TxObject().(*txobj.SimpleTransfer); ok {
+			for _, out := range tr.Outs {
+				addrs[string(out.To)] = true
+				if bytes.Equal(out.Asset, assets.MDC) {
+					res.Volume = res.Volume.Add(out.Amount)
+				}
+			}
+		}
+		res.TotalFee = res.TotalFee.Add(txFee(c.bc.Cfg, tx))
+	}
+	res.UniqueAddresses = len(addrs)
+	if n := len(block.Txs); n > 0 {
+		res.AvgFee = res.TotalFee.Div(bignum.NewInt(int64(n)))
+	}
+	c.Cache.Set(key, bin.Encode(res), blockOverviewCacheTTL)
+	return res
+}
+
+var errNoFeeBurning = errors.New("501 - Blockchain doesn't burn fees: all fees are paid to block producer")
+
+// blockFeeSplit handles /block/<num>/fee-split.
+// Fee policy of chain config has no burning, so there is no split of fees to report.
+func (c *Context) blockFeeSplit(num uint64) interface{} {
+	c.abort(errNoFeeBurning, http.StatusNotImplemented)
+	return nil
+}
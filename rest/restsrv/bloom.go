@@ -0,0 +1,130 @@
+package restsrv
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/txobj"
+)
+
+// Bloom filters of addresses are compatible with BIP37: bit array, count of hash functions and tweak;
+// i-th hash of element is murmur3(seed: i*0xFBA4C795+tweak) mod count of bits.
+const (
+	maxBloomFilterSize = 36000 // max size of bloom filter (in bytes)
+	maxBloomHashFuncs  = 50    // max count of hash functions of bloom filter
+)
+
+var errInvalidBloomFilter = fmt.Errorf("400 - Invalid bloom filter (expected 1..%d bytes and 1..%d hash functions)", maxBloomFilterSize, maxBloomHashFuncs)
+
+type bloomFilter struct {
+	bits      []byte
+	hashFuncs uint32
+	tweak     uint32
+}
+
+func (f *bloomFilter) contains(data []byte) bool {
+	n := uint32(len(f.bits) * 8)
+	for i := uint32(0); i < f.hashFuncs; i++ {
+		idx := murmur3(i*0xFBA4C795+f.tweak, data) % n
+		if f.bits[idx>>3]&(1<<(idx&7)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// matchTx returns true if sender or any recipient of transaction matches filter
+func (f *bloomFilter) matchTx(tx *chain.Transaction) bool {
+	if tx.Sender != nil && f.contains(tx.Sender.Address()) {
+		return true
+	}
+	if tr, ok := tx.TxObject().(*txobj.SimpleTransfer); ok {
+		for _, out := range tr.Outs {
+			if f.contains(out.To) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// murmur3 returns 32-bit MurmurHash3 of data
+func murmur3(seed uint32, data []byte) uint32 {
+	const c1, c2 = 0xcc9e2d51, 0x1b873593
+	h, n := seed, len(data)
+	for ; len(data) >= 4; data = data[4:] {
+		k := uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16 | uint32(data[3])<<24
+		k = bits.RotateLeft32(k*c1, 15) * c2
+		h = bits.RotateLeft32(h^k, 13)*5 + 0xe6546b64
+	}
+	var k uint32
+	switch len(data) {
+	case 3:
+		k ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(data[0])
+		h ^= bits.RotateLeft32(k*c1, 15) * c2
+	}
+	h ^= uint32(n)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// getBloomFilter returns bloom filter passed as params "filter" (hex), "hash_funcs", "tweak"
+// or as binary request body (bits, hash_funcs, tweak)
+func (c *Context) getBloomFilter() *bloomFilter {
+	f := &bloomFilter{}
+	if s := c.getStr("filter", ""); s != "" {
+		var err error
+		f.bits, err = hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		c.assert(err)
+		f.hashFuncs, f.tweak = uint32(c.getUint("hash_funcs")), uint32(c.getUint("tweak"))
+	} else {
+		c.getBinary(&f.bits, &f.hashFuncs, &f.tweak)
+	}
+	if len(f.bits) == 0 || len(f.bits) > maxBloomFilterSize || f.hashFuncs == 0 || f.hashFuncs > maxBloomHashFuncs {
+		c.assert(errInvalidBloomFilter)
+	}
+	return f
+}
+
+// filteredTxs handles /txs/filtered?filter=<hex>&hash_funcs=<n>&tweak=<n>&from=<block-num>&to=<block-num>.
+// Matched transactions include false positives of filter; client has to check addresses of transactions itself.
+func (c *Context) filteredTxs() []*chain.Transaction {
+	f := c.getBloomFilter()
+	last := c.bc.LastBlock()
+	if last == nil {
+		return []*chain.Transaction{}
+	}
+	from, to := c.getUint("from"), last.Num
+	if c.exists("to") {
+		to = c.getUint("to")
+	}
+	if from > to {
+		c.assert(errors.New("400 - Invalid block range"))
+	}
+	if to-from+1 > c.cfg.MaxBlocksWindow {
+		c.assert(fmt.Errorf("400 - Block range is too large (max %d blocks)", c.cfg.MaxBlocksWindow))
+	}
+	txs := []*chain.Transaction{}
+	c.scanBlocks(from, to, func(block *chain.Block) {
+		for _, tx := range block.Txs {
+			if f.matchTx(tx) {
+				txs = append(txs, tx)
+			}
+		}
+	})
+	return txs
+}
@@ -0,0 +1,88 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// zeroReader returns n zero bytes without allocating them
+type zeroReader struct{ n int64 }
+
+func (r *zeroReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.n {
+		p = p[:r.n]
+	}
+	for i := range p {
+		p[i] = 0
+	}
+	r.n -= int64(len(p))
+	return len(p), nil
+}
+
+const testMaxBodySize = 64 * 1024
+
+// execPutTx returns http-status of /put-tx request with binary body and count of bytes allocated by request
+func execPutTx(body io.Reader) (status int, allocated uint64) {
+	s := &Server{}
+	s.setConfig(&Config{MaxBodySize: testMaxBodySize})
+	req := httptest.NewRequest("POST", "/put-tx", body)
+	req.Header.Set("Content-Type", contentTypeOctetStream)
+	rw := httptest.NewRecorder()
+
+	var m0, m1 runtime.MemStats
+	runtime.ReadMemStats(&m0)
+	func() {
+		defer func() { recover() }()
+		newContext(s, req, rw).Exec()
+	}()
+	runtime.ReadMemStats(&m1)
+	return rw.Code, m1.TotalAlloc - m0.TotalAlloc
+}
+
+func TestContext_Exec_OversizedBody(t *testing.T) {
+	status, allocated := execPutTx(&zeroReader{256 << 20})
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.True(t, allocated < 16*testMaxBodySize, "allocated %d bytes", allocated)
+}
+
+func TestContext_Exec_BodyWithHugeLengthPrefix(t *testing.T) {
+	// small body whose length prefixes declare gigabytes of data
+	body := bytes.Repeat([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}, 4)
+
+	status, allocated := execPutTx(bytes.NewReader(body))
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.True(t, allocated < 16*testMaxBodySize, "allocated %d bytes", allocated)
+}
+
+func TestContext_getSubmittedTx_JSON(t *testing.T) {
+	bc := newTestChain(t)
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().Address()
+	tx := txobj.NewSimpleTransfer(bc, crypto.NewPrivateKeyBySecret("sender"), assets.MDC, bignum.NewInt(assets.Coin), 0, to, 0, "", 7)
+	data, err := json.Marshal(tx) // as node writes JSON of transaction
+	assert.NoError(t, err)
+	req := httptest.NewRequest("POST", "/put-tx?nonce=1", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	c, _ := newTestContext(req)
+
+	submitted := c.getSubmittedTx()
+
+	assert.Equal(t, tx.Hash(), submitted.Hash())
+	assert.NoError(t, submitted.Verify(bc.Cfg))
+	assert.Equal(t, "1", c.getStr("nonce", "")) // fields of transaction aren't params
+}
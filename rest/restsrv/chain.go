@@ -0,0 +1,145 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+)
+
+var errNotImplemented = errors.New("501 - Not supported by blockchain node")
+
+// assertSupported aborts request with 501-response if capability required by request is not available (e.g. flushing of response)
+func (c *Context) assertSupported(ok bool) {
+	if !ok {
+		c.abort(errNotImplemented, http.StatusNotImplemented)
+	}
+}
+
+// userByAddress returns user registered with given address or nil.
+// Storage looks up users only by nick, so transactions of address are scanned for registration of user sent by address;
+// registration is confirmed by UserByNick (the user of nick has the same address). Found users are cached per request.
+func (c *Context) userByAddress(addr []byte) (user *txobj.User, err error) {
+	if user, ok := c.users[string(addr)]; ok {
+		return user, nil
+	}
+	var nicks []string
+	c.scanAddressTxs(assets.MDC, addr, 0, false, maxScanTxs, func(tx *chain.Transaction) bool {
+		if u, ok := tx.TxObject().(*txobj.User); ok && tx.Sender != nil && bytes.Equal(tx.Sender.Address(), addr) {
+			nicks = append(nicks, u.Nick)
+		}
+		return true
+	})
+	for _, nick := range nicks {
+		if user, err = c.bc.UserByNick(nick); err != nil {
+			return nil, err
+		}
+		if user != nil && bytes.Equal(user.PublicKey().Address(), addr) {
+			break
+		}
+		user = nil
+	}
+	if c.users == nil {
+		c.users = map[string]*txobj.User{}
+	}
+	c.users[string(addr)] = user
+	return user, nil
+}
+
+// submittedPending returns unconfirmed transactions put to mempool through REST service (in order of submission)
+func (c *Context) submittedPending() []*chain.Transaction {
+	pending := c.submitted.pending(c.isConfirmed, time.Now())
+	txs := make([]*chain.Transaction, len(pending))
+	for i, st := range pending {
+		txs[i] = st.tx
+	}
+	return txs
+}
+
+var errNoStateProofs = errors.New("501 - Blockchain has no state trie: balances can't be proven to light clients")
+
+// balanceProof handles /address/<address>/balance-proof.
+// Blocks don't commit to state root, so there is no proof of balance which could be checked against trusted header.
+func (c *Context) balanceProof() {
+	c.WriteError(errNoStateProofs, http.StatusNotImplemented)
+}
+
+var errAccountBasedChain = errors.New("501 - Blockchain is account-based: transaction outputs are not spent by other transactions")
+
+// spentBy handles /tx/<hash>/output/<index>/spent-by.
+// MDC is account-based: outputs of transfers credit balances and are never spent by other transactions.
+func (c *Context) spentBy() interface{} {
+	c.abort(errAccountBasedChain, http.StatusNotImplemented)
+	return nil
+}
+
+// knownAssets returns ids of assets issued in blockchain (MDC is the first)
+func (c *Context) knownAssets() [][]byte {
+	list, err := c.index.assetList()
+	c.assert(err)
+	return list
+}
+
+// assetExists returns false if asset is not issued in blockchain
+func (c *Context) assetExists(asset []byte) bool {
+	for _, a := range c.knownAssets() {
+		if bytes.Equal(a, asset) {
+			return true
+		}
+	}
+	return false
+}
+
+// assetBySymbol returns asset id by display symbol (case-insensitive)
+func (c *Context) assetBySymbol(symbol string) (asset []byte, ok bool) {
+	if strings.EqualFold(symbol, "MDC") {
+		return assets.MDC, true
+	}
+	for s, asset := range c.AssetSymbols {
+		if strings.EqualFold(s, symbol) {
+			return asset, true
+		}
+	}
+	return nil, false
+}
+
+// assetInfo returns display symbol and decimal places of asset.
+// Blockchain keeps amounts of all assets in base units of assets.Coin;
+// asset without symbol in Server.AssetSymbols is displayed by hex-encoded id.
+func (c *Context) assetInfo(asset []byte) (symbol string, decimals int) {
+	if bytes.Equal(asset, assets.MDC) {
+		return "MDC", coinDecimals
+	}
+	for s, a := range c.AssetSymbols {
+		if bytes.Equal(a, asset) {
+			return s, coinDecimals
+		}
+	}
+	return "0x" + hex.EncodeToString(asset), coinDecimals
+}
+
+type managedAsset struct {
+	Asset       string   `json:"asset"`
+	Permissions []string `json:"permissions"` // "issuer"
+}
+
+// managedAssets handles /address/<address>/managed-assets.
+// Blockchain has no management rights of assets, so address manages assets which it issued
+// (is sender of creation transaction of asset).
+func (c *Context) managedAssets(addr []byte) []*managedAsset {
+	res := []*managedAsset{}
+	for _, asset := range c.knownAssets() {
+		tx, err := c.index.assetIssue(asset)
+		c.assert(err)
+		if tx != nil && tx.Sender != nil && bytes.Equal(tx.Sender.Address(), addr) {
+			res = append(res, &managedAsset{Asset: hex.EncodeToString(asset), Permissions: []string{"issuer"}})
+		}
+	}
+	return res
+}
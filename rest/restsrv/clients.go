@@ -0,0 +1,100 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ClientProfile is default response options of authenticated API client.
+// Explicit request headers and params always override them.
+type ClientProfile struct {
+	Format   string `json:"format"`    // json (default) | binary | protobuf | ndjson
+	PageSize int64  `json:"page_size"` // default "limit" of lists
+	Naming   string `json:"naming"`    // snake_case (default) | camelCase
+}
+
+var clientFormats = map[string]string{
+	"json":     contentTypeJSON,
+	"binary":   contentTypeBinary,
+	"protobuf": contentTypeProtobuf,
+	"ndjson":   contentTypeNDJSON,
+}
+
+// apiKey returns API key of request (header X-API-Key or "Authorization: Bearer <key>")
+func (c *Context) apiKey() string {
+	if key := c.req.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	return strings.TrimPrefix(c.req.Header.Get("Authorization"), "Bearer ")
+}
+
+// applyClientProfile sets defaults of client profile for response options not given by request
+func (c *Context) applyClientProfile() {
+	if c.ClientProfiles == nil {
+		return
+	}
+	key := c.apiKey()
+	p := c.ClientProfiles[key]
+	if key == "" || p == nil {
+		return
+	}
+	if accept := c.req.Header.Get("Accept"); accept == "" || accept == "*/*" {
+		if ct, ok := clientFormats[p.Format]; ok {
+			c.req.Header.Set("Accept", ct)
+		}
+	}
+	if p.PageSize > 0 && !c.exists("limit") {
+		c.reqQuery.Set("limit", strconv.FormatInt(p.PageSize, 10))
+	}
+	if p.Naming != "" && !c.exists("naming") {
+		c.reqQuery.Set("naming", p.Naming)
+	}
+}
+
+// wantsCamelCase returns true if client asks for camelCase names of JSON fields (param "naming=camelCase")
+func (c *Context) wantsCamelCase() bool {
+	return c.getStr("naming", "") == "camelCase"
+}
+
+// camelCaseKeys returns JSON-value with field names converted from snake_case to camelCase
+func camelCaseKeys(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj interface{}
+	if dec.Decode(&obj) != nil {
+		return v
+	}
+	return camelCaseValue(obj)
+}
+
+func camelCaseValue(obj interface{}) interface{} {
+	switch val := obj.(type) {
+	case []interface{}:
+		for i, item := range val {
+			val[i] = camelCaseValue(item)
+		}
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			res[camelCase(k)] = camelCaseValue(item)
+		}
+		return res
+	}
+	return obj
+}
+
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if p := parts[i]; p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
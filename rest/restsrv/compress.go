@@ -0,0 +1,63 @@
+package restsrv
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const minCompressSize = 1024 // responses smaller than this are sent uncompressed
+
+// acceptedEncoding returns "gzip" or "deflate" if client accepts compressed responses (header Accept-Encoding)
+func (c *Context) acceptedEncoding() string {
+	accepted := map[string]bool{}
+	for _, s := range strings.Split(c.req.Header.Get("Accept-Encoding"), ",") {
+		parts := strings.Split(s, ";")
+		if len(parts) > 1 && strings.Replace(parts[1], " ", "", -1) == "q=0" {
+			continue
+		}
+		accepted[strings.ToLower(strings.TrimSpace(parts[0]))] = true
+	}
+	if accepted["gzip"] {
+		return "gzip"
+	} else if accepted["deflate"] {
+		return "deflate"
+	}
+	return ""
+}
+
+// writeBody writes response with http-status (0 - 200). Body is compressed if client accepts compression,
+// body isn't small and isn't binary-encoded (binary clients may not expect compression).
+func (c *Context) writeBody(httpCode int, body io.Reader) error {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	c.storeIdempotent(httpCode, data)
+	h := c.rw.Header()
+	if !isBinaryContentType(h.Get("Content-Type")) {
+		h.Add("Vary", "Accept-Encoding")
+		if enc := c.acceptedEncoding(); enc != "" && len(data) >= minCompressSize {
+			var buf bytes.Buffer
+			var w io.WriteCloser
+			if enc == "gzip" {
+				w = gzip.NewWriter(&buf)
+			} else {
+				w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+			}
+			w.Write(data)
+			w.Close()
+			data = buf.Bytes()
+			h.Set("Content-Encoding", enc)
+		}
+	}
+	if httpCode != 0 {
+		c.writeHeader(httpCode)
+	}
+	c.written = true
+	_, err = c.rw.Write(data)
+	return err
+}
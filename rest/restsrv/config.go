@@ -1,15 +1,135 @@
 package restsrv
 
-import "flag"
+import (
+	"bufio"
+	"flag"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
 
 type Config struct {
-	HTTPConn string
+	HTTPConn   string `json:"http"`
+	MinFeeRate int64  `json:"min_fee_rate"` // minimal fee per byte; fee is fixed (TxFee), so it caps size of transaction by TxFee/MinFeeRate bytes (0 - no cap)
+	WSSyncRate int    `json:"ws_sync_rate"` // max blocks per second sent by /ws/sync while backfilling (0 - unlimited)
+
+	MaxSubscriptions      int `json:"max_subscriptions"`        // max concurrent WebSocket/SSE subscriptions (0 - unlimited)
+	MaxSubscriptionsPerIP int `json:"max_subscriptions_per_ip"` // max concurrent subscriptions of client IP (0 - unlimited)
+	SubscriptionBuffer    int `json:"subscription_buffer"`      // messages buffered per subscription; slow consumers are dropped
+
+	MaxBlocksWindow uint64 `json:"max_blocks_window"` // max count of blocks scanned by statistic requests
+	MaxBodySize     int64  `json:"max_body_size"`     // max size of request body (in bytes)
+
+	QueryTimeout time.Duration `json:"query_timeout"` // max duration of chain query (0 - unlimited)
+
+	RateLimit     float64 `json:"rate_limit"`      // write requests per second per client IP (0 - unlimited)
+	RateBurst     int     `json:"rate_burst"`      // max burst of write requests per client IP
+	ReadRateLimit float64 `json:"read_rate_limit"` // read requests per second per client IP (0 - unlimited)
+	ReadRateBurst int     `json:"read_rate_burst"` // max burst of read requests per client IP
+	TrustProxy    bool    `json:"trust_proxy"`     // take client IP from header X-Forwarded-For (node is behind reverse proxy)
+
+	CursorSecret string        `json:"-"`          // secret key of pagination cursors (random by default)
+	CursorTTL    time.Duration `json:"cursor_ttl"` // lifetime of pagination cursors
+
+	ProfilesFile string `json:"profiles"` // JSON-file of response projection profiles
+
+	RequestIDHeader string `json:"request_id_header"` // header of request id echoed in response (empty - don't accept and echo)
+
+	AdminKey   string `json:"-"`      // API key of admin endpoints (admin endpoints are disabled if empty)
+	ConfigFile string `json:"config"` // file of mutable options (reloaded by /admin/reload)
 }
 
 func NewConfig() *Config {
 	cfg := &Config{
-		HTTPConn: "127.0.0.1:8777",
+		HTTPConn:   "127.0.0.1:8777",
+		WSSyncRate: 200,
+
+		MaxSubscriptions:      1000,
+		MaxSubscriptionsPerIP: 10,
+		SubscriptionBuffer:    64,
+
+		MaxBlocksWindow: 10000,
+		MaxBodySize:     4 * 1024 * 1024,
+
+		QueryTimeout: 15 * time.Second,
+
+		RateBurst:     20, // rate limits are off by default (node behind reverse proxy sees one client IP)
+		ReadRateBurst: 200,
+
+		CursorTTL: time.Hour,
+
+		RequestIDHeader: "X-Request-ID",
 	}
-	flag.StringVar(&cfg.HTTPConn, "http", cfg.HTTPConn, "REST API http connection")
+	cfg.bindFlags(flag.CommandLine)
 	return cfg
 }
+
+func (cfg *Config) bindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&cfg.HTTPConn, "http", cfg.HTTPConn, "REST API http connection")
+	fs.Int64Var(&cfg.MinFeeRate, "min-fee-rate", cfg.MinFeeRate, "REST API minimal transaction fee per byte; fee is fixed, so transactions larger than TxFee/min-fee-rate bytes are rejected (0 - no cap)")
+	fs.IntVar(&cfg.WSSyncRate, "ws-sync-rate", cfg.WSSyncRate, "REST API max blocks per second streamed by /ws/sync backfill (0 - unlimited)")
+	fs.IntVar(&cfg.MaxSubscriptions, "max-subscriptions", cfg.MaxSubscriptions, "REST API max concurrent WebSocket/SSE subscriptions (0 - unlimited)")
+	fs.IntVar(&cfg.MaxSubscriptionsPerIP, "max-subscriptions-per-ip", cfg.MaxSubscriptionsPerIP, "REST API max concurrent subscriptions of client IP (0 - unlimited)")
+	fs.IntVar(&cfg.SubscriptionBuffer, "subscription-buffer", cfg.SubscriptionBuffer, "REST API messages buffered per subscription (slow consumers are dropped)")
+	fs.Uint64Var(&cfg.MaxBlocksWindow, "max-blocks-window", cfg.MaxBlocksWindow, "REST API max count of blocks scanned by statistic requests")
+	fs.Int64Var(&cfg.MaxBodySize, "max-body-size", cfg.MaxBodySize, "REST API max size of request body (in bytes)")
+	fs.DurationVar(&cfg.QueryTimeout, "query-timeout", cfg.QueryTimeout, "REST API max duration of chain query (0 - unlimited)")
+	fs.Float64Var(&cfg.RateLimit, "rate-limit", cfg.RateLimit, "REST API write requests per second per client IP (0 - unlimited)")
+	fs.IntVar(&cfg.RateBurst, "rate-burst", cfg.RateBurst, "REST API max burst of write requests per client IP")
+	fs.Float64Var(&cfg.ReadRateLimit, "read-rate-limit", cfg.ReadRateLimit, "REST API read requests per second per client IP (0 - unlimited)")
+	fs.IntVar(&cfg.ReadRateBurst, "read-rate-burst", cfg.ReadRateBurst, "REST API max burst of read requests per client IP")
+	fs.BoolVar(&cfg.TrustProxy, "trust-proxy", cfg.TrustProxy, "REST API take client IP from header X-Forwarded-For (node is behind reverse proxy)")
+	fs.StringVar(&cfg.CursorSecret, "cursor-secret", cfg.CursorSecret, "REST API secret key of pagination cursors (random by default)")
+	fs.DurationVar(&cfg.CursorTTL, "cursor-ttl", cfg.CursorTTL, "REST API lifetime of pagination cursors")
+	fs.StringVar(&cfg.ProfilesFile, "profiles", cfg.ProfilesFile, "REST API JSON-file of response projection profiles ({<profile>:{<endpoint>:[<field>,...]}})")
+	fs.StringVar(&cfg.RequestIDHeader, "request-id-header", cfg.RequestIDHeader, "REST API header of request id echoed in response (empty - don't accept and echo)")
+	fs.StringVar(&cfg.AdminKey, "admin-key", cfg.AdminKey, "REST API key of admin endpoints (disabled if empty)")
+	fs.StringVar(&cfg.ConfigFile, "config", cfg.ConfigFile, "REST API file of options reloaded by /admin/reload (line format: <option>=<value>)")
+}
+
+// reload returns copy of config with options read from config file
+// and names of changed options which require restart (they keep current values).
+func (cfg *Config) reload() (newCfg *Config, restartRequired []string, err error) {
+	lines, err := readConfigFile(cfg.ConfigFile)
+	if err != nil {
+		return
+	}
+	c := *cfg
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	c.bindFlags(fs)
+	if err = fs.Parse(lines); err != nil {
+		return
+	}
+	restartRequired = []string{}
+	for name, v := range map[string][2]*string{
+		"http":          {&c.HTTPConn, &cfg.HTTPConn},
+		"cursor-secret": {&c.CursorSecret, &cfg.CursorSecret},
+		"admin-key":     {&c.AdminKey, &cfg.AdminKey},
+		"config":        {&c.ConfigFile, &cfg.ConfigFile},
+		"profiles":      {&c.ProfilesFile, &cfg.ProfilesFile},
+	} {
+		if *v[0] != *v[1] {
+			restartRequired = append(restartRequired, name)
+			*v[0] = *v[1]
+		}
+	}
+	sort.Strings(restartRequired)
+	return &c, restartRequired, nil
+}
+
+// readConfigFile returns options of config file as command line args
+func readConfigFile(filename string) (args []string, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			args = append(args, "-"+strings.TrimPrefix(line, "-"))
+		}
+	}
+	return args, sc.Err()
+}
@@ -0,0 +1,135 @@
+package restsrv
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/common/bignum"
+)
+
+// ConfirmationTier recommends count of confirmations for payments worth at least MinRewards average block rewards
+type ConfirmationTier struct {
+	MinRewards    float64
+	Confirmations int
+}
+
+// ConfirmationPolicy recommends count of confirmations by payment amount relative to recent block rewards:
+// reverting payment which is worth more block rewards costs attacker more blocks.
+type ConfirmationPolicy struct {
+	Tiers        []ConfirmationTier // sorted by MinRewards
+	RewardWindow uint64             // count of the last blocks of average block reward
+	MinReward    int64              // floor of average block reward in base units (for chains with low fees)
+}
+
+// DefaultConfirmationPolicy returns default confirmation policy of Server
+func DefaultConfirmationPolicy() *ConfirmationPolicy {
+	return &ConfirmationPolicy{
+		Tiers:        []ConfirmationTier{{0, 1}, {10, 3}, {100, 6}, {1000, 20}},
+		RewardWindow: 100,
+		MinReward:    100 * assets.Coin,
+	}
+}
+
+// Confirmations returns recommended count of confirmations for amount by average block reward
+// and amount in block rewards
+func (p *ConfirmationPolicy) Confirmations(amount, avgReward bignum.Int) (n int, rewards float64) {
+	if avgReward = p.blockReward(avgReward); avgReward.Sign() > 0 {
+		rewards, _ = new(big.Rat).SetFrac(amount.BigInt(), avgReward.BigInt()).Float64()
+	}
+	n = 1
+	for _, t := range p.Tiers {
+		if rewards >= t.MinRewards {
+			n = t.Confirmations
+		}
+	}
+	return
+}
+
+// blockReward returns average block reward floored by MinReward
+func (p *ConfirmationPolicy) blockReward(avgReward bignum.Int) bignum.Int {
+	if floor := bignum.NewInt(p.MinReward); avgReward.Cmp(floor) < 0 {
+		return floor
+	}
+	return avgReward
+}
+
+// avgBlockReward returns average reward paid to producers of the last blocks (all fees of block transactions)
+func (c *Context) avgBlockReward(window uint64) bignum.Int {
+	last := c.bc.LastBlock()
+	if last == nil || window == 0 {
+		return bignum.NewInt(0)
+	}
+	key := fmt.Sprintf("avg-block-reward/%d/%d", last.Num, window)
+	if data, ok := c.Cache.Get(key); ok {
+		return bignum.NewFromBig(new(big.Int).SetBytes(data))
+	}
+	if window > last.Num+1 {
+		window = last.Num + 1
+	}
+	total := bignum.NewInt(0)
+	c.scanBlocks(last.Num+1-window, last.Num, func(block *chain.Block) {
+		for _, tx := range block.Txs {
+			total = total.Add(txFee(c.bc.Cfg, tx))
+		}
+	})
+	avg := total.Div(bignum.NewInt(int64(window)))
+	c.Cache.Set(key, avg.BigInt().Bytes(), time.Minute)
+	return avg
+}
+
+// blockTime returns time of block timestamp (in microseconds)
+func blockTime(ts int64) time.Time {
+	return time.Unix(0, ts*int64(time.Microsecond)).UTC()
+}
+
+// avgBlockTime returns average interval between the last blocks
+func (c *Context) avgBlockTime(window uint64) time.Duration {
+	last := c.bc.LastBlock()
+	if last == nil || last.Num < 1 {
+		return 0
+	}
+	if window > last.Num {
+		window = last.Num
+	}
+	first, err := c.bc.GetBlock(last.Num - window)
+	c.assert(err)
+	if first == nil {
+		return 0
+	}
+	return blockTime(last.Timestamp).Sub(blockTime(first.Timestamp)) / time.Duration(window)
+}
+
+type confirmationsAdvice struct {
+	Amount         bignum.Int `json:"amount"`
+	AvgBlockReward bignum.Int `json:"avg_block_reward"` // of the last blocks (floored by policy)
+	Rewards        float64    `json:"amount_in_rewards"`
+	Confirmations  int        `json:"confirmations"`
+	AvgBlockTime   float64    `json:"avg_block_time"` // in seconds
+	ETA            float64    `json:"eta"`            // in seconds
+}
+
+// recommendedConfirmations handles /confirmations/recommended?amount=<num>&asset=<asset>
+func (c *Context) recommendedConfirmations() *confirmationsAdvice {
+	amount := c.getAmount("amount")
+	if asset := c.getStr("asset", ""); asset != "" && asset != "MDC" {
+		c.assert(fmt.Errorf("400 - Unknown asset"))
+	}
+	policy := c.ConfirmationPolicy
+	if policy == nil {
+		policy = DefaultConfirmationPolicy()
+	}
+	reward := c.avgBlockReward(policy.RewardWindow)
+	n, rewards := policy.Confirmations(amount, reward)
+	avg := c.avgBlockTime(100)
+	return &confirmationsAdvice{
+		Amount:         amount,
+		AvgBlockReward: policy.blockReward(reward),
+		Rewards:        rewards,
+		Confirmations:  n,
+		AvgBlockTime:   avg.Seconds(),
+		ETA:            (avg * time.Duration(n)).Seconds(),
+	}
+}
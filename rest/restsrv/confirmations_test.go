@@ -0,0 +1,50 @@
+package restsrv
+
+import (
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/stretchr/testify/assert"
+)
+
+func coins(n int64) bignum.Int {
+	return bignum.NewInt(n * assets.Coin)
+}
+
+func TestConfirmationPolicy_Confirmations(t *testing.T) {
+	p := DefaultConfirmationPolicy()
+
+	for amount, exp := range map[int64]int{
+		1:       1,
+		9999:    1,
+		10000:   3, // 10 rewards of 1000 coins
+		100000:  6,
+		5000000: 20,
+	} {
+		n, _ := p.Confirmations(coins(amount), coins(1000))
+
+		assert.Equal(t, exp, n, amount)
+	}
+}
+
+func TestConfirmationPolicy_Confirmations_ScaledByReward(t *testing.T) {
+	p := DefaultConfirmationPolicy()
+
+	n1, rewards1 := p.Confirmations(coins(20000), coins(1000))
+	n2, rewards2 := p.Confirmations(coins(20000), coins(100000))
+
+	assert.Equal(t, 3, n1)
+	assert.Equal(t, 20.0, rewards1)
+	assert.Equal(t, 1, n2)
+	assert.Equal(t, 0.2, rewards2)
+}
+
+func TestConfirmationPolicy_Confirmations_MinReward(t *testing.T) {
+	p := DefaultConfirmationPolicy()
+
+	n, rewards := p.Confirmations(coins(1000), bignum.NewInt(0)) // no fees in the last blocks
+
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 10.0, rewards)
+}
@@ -0,0 +1,15 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+)
+
+var errNotEpochBased = errors.New("501 - Blockchain consensus is not epoch-based")
+
+// consensusEpoch handles /consensus/epoch.
+// Consensus of MDC blockchain has no epochs and validator sets.
+func (c *Context) consensusEpoch() interface{} {
+	c.abort(errNotEpochBased, http.StatusNotImplemented)
+	return nil
+}
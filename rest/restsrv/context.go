@@ -5,7 +5,9 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -23,12 +25,25 @@ import (
 
 type Context struct {
 	*Server
+	cfg      *Config // snapshot of actual config
+	reqID    string
 	req      *http.Request
 	reqQuery url.Values
-	reqBody  *bin.Reader
 	rw       http.ResponseWriter
 	uriPath  string
 	uriParts []string
+
+	bodyParams url.Values // params of POST/PUT request body (JSON object or form-encoded)
+	bodyErr    error      // error of parsing request body params
+	bodyJSON   []byte     // JSON request body
+
+	deprecation    *deprecationNotice     // notice of deprecated route or param used by request
+	httpStatus     int                    // http-status of successful response (0 - 200)
+	route          string                 // route of request for metrics
+	written        bool                   // response (or its header) is written
+	idempotencyKey string                 // key of response stored for repeated write requests (see replayIdempotent)
+	accept         *acceptedMedia         // response format negotiated by header Accept
+	users          map[string]*txobj.User // users found by address (see userByAddress)
 }
 
 func newContext(
@@ -41,17 +56,24 @@ func newContext(
 	if path != "/" {
 		path = strings.TrimSuffix(path, "/")
 	}
+	cfg := srv.config()
+	if req.Body != nil && cfg.MaxBodySize > 0 {
+		req.Body = http.MaxBytesReader(rw, req.Body, cfg.MaxBodySize)
+	}
 	c := &Context{
 		Server:   srv,
+		cfg:      cfg,
 		req:      req,
 		uriPath:  path,
 		reqQuery: req.URL.Query(),
-		reqBody:  bin.NewReader(req.Body),
 		rw:       rw,
 	}
-	if (req.Method == "POST" || req.Method == "PUT") && req.ParseForm() == nil {
-		c.reqQuery = req.Form
+	if (req.Method == "POST" || req.Method == "PUT") && path != "/put-tx" { // body of /put-tx is transaction
+		c.bodyParams, c.bodyErr = c.parseBodyParams()
 	}
+	c.initRequestID()
+	c.applyClientProfile()
+	c.checkDeprecations()
 	return c
 }
 
@@ -60,69 +82,397 @@ const (
 	contentTypeJSON   = "application/json; charset=utf-8"
 )
 
+// address as MDC<base58> | @<nick> | 0x<userID:hex>
+const reAddress = `(@[a-zA-Z0-9\-_]+|MDC[a-zA-Z1-9]+|0x[a-f0-9]+)`
+
 var (
 	rePathBlockNum    = regexp.MustCompile(`^/block/(\d+)$`)
-	rePathAddressInfo = regexp.MustCompile(`^/address/(@[a-zA-Z0-9\-_]+|MDC[a-zA-Z1-9]+|0x[a-f0-9]+)$`)
+	rePathBlockHash   = regexp.MustCompile(`^/block/([a-f0-9]{64})$`) // checked before rePathBlockNum
+	rePathBlockTxs    = regexp.MustCompile(`^/block/(\d+)/txs$`)
+	rePathBlockOvw    = regexp.MustCompile(`^/block/(\d+)/overview$`)
+	rePathBlockFees   = regexp.MustCompile(`^/block/(\d+)/fee-split$`)
+	rePathBlockFinal  = regexp.MustCompile(`^/block/(\d+)/finality$`)
+	rePathAddressInfo = regexp.MustCompile(`^/address/` + reAddress + `$`)
+	rePathAddrTxs     = regexp.MustCompile(`^/address/` + reAddress + `/txs$`)
+	rePathAddrStuck   = regexp.MustCompile(`^/address/` + reAddress + `/stuck$`)
+	rePathAddrPayURI  = regexp.MustCompile(`^/address/` + reAddress + `/payment-uri$`)
+	rePathAddrProof   = regexp.MustCompile(`^/address/` + reAddress + `/balance-proof$`)
+	rePathAddrActs    = regexp.MustCompile(`^/address/` + reAddress + `/activity$`)
+	rePathAddrBalBrk  = regexp.MustCompile(`^/address/` + reAddress + `/balance-breakdown$`)
+	rePathAddrValue   = regexp.MustCompile(`^/address/` + reAddress + `/total-value$`)
+	rePathAddrDelta   = regexp.MustCompile(`^/address/` + reAddress + `/delta$`)
+	rePathAddrName    = regexp.MustCompile(`^/address/` + reAddress + `/name$`)
+	rePathAddrBals    = regexp.MustCompile(`^/address/` + reAddress + `/balances$`)
+	rePathAddrBalsAt  = regexp.MustCompile(`^/address/` + reAddress + `/balances-at$`)
+	rePathAddrFunding = regexp.MustCompile(`^/address/` + reAddress + `/funding-source$`)
+	rePathAddrPayees  = regexp.MustCompile(`^/address/` + reAddress + `/payees$`)
+	rePathAddrAssets  = regexp.MustCompile(`^/address/` + reAddress + `/managed-assets$`)
+	rePathUserUpline  = regexp.MustCompile(`^/user/(0x[a-f0-9]{1,16}|\d+)/upline$`)
+	rePathUserCreated = regexp.MustCompile(`^/user/(0x[a-f0-9]{1,16}|\d+)/creation-tx$`)
+	rePathAssetCrtd   = regexp.MustCompile(`^/asset/([a-f0-9]+)/creation-tx$`)
+	rePathMempoolPkg  = regexp.MustCompile(`^/mempool/([a-f0-9]{64})/package$`)
+	rePathSubmitPkg   = regexp.MustCompile(`^/submitted/([a-f0-9]{64})/package$`)
 	reTxHash          = regexp.MustCompile(`^/tx/([a-f0-9]{64})$`)
-	reTxID            = regexp.MustCompile(`^/tx/([a-f0-9]{1,16})$`)
+	reTxID            = regexp.MustCompile(`^/tx/([a-f0-9]+)$`) // checked after reTxHash; ids longer than 16 digits are rejected
+	reTxConfirms      = regexp.MustCompile(`^/tx/([a-f0-9]{64})/confirmations$`)
+	reTxOutSpentBy    = regexp.MustCompile(`^/tx/([a-f0-9]{64})/output/(\d+)/spent-by$`)
 
 	err404        = errors.New("404 - Not found")
 	errUserExists = errors.New("400 - User exists")
+	errEmptyRef   = errors.New("400 - Empty reference")
+
+	errUnknownAsset = errors.New("400 - Unknown asset")
 )
 
 func (c *Context) Exec() {
+	if c.serveCORS() { // preflight request
+		return
+	}
+	c.assertMethod()
+	c.assertRateLimit()
+	c.assert(c.bodyErr)
 
+	defer func() {
+		if c.route == "" { // request path is route
+			c.route = c.uriPath
+		}
+	}()
+	if c.replayIdempotent() {
+		return
+	}
 	switch {
 
 	case c.uriPath == "/info":
-		c.WriteVar(c.bc.Info())
+		info := func() (interface{}, error) {
+			return c.shared(func() (interface{}, error) {
+				return c.bc.Info()
+			})
+		}
+		if last := c.bc.LastBlock(); last != nil { // info changes with new blocks
+			c.writeModified(blockTime(last.Timestamp), info)
+			return
+		}
+		c.writeMutable(info())
+
+	case c.uriPath == "/metrics":
+		c.serveMetrics()
+
+	case c.uriPath == "/status":
+		c.writeMutable(c.nodeStatus())
+
+		//	/block/<hash:hex>
+	case c.matchPath(rePathBlockHash):
+		blockHash, _ := hex.DecodeString(c.uriParts[1])
+		block, err := c.blockByHash(blockHash)
+		if err == nil && block == nil {
+			err = errBlockNotFound
+		}
+		c.writeImmutable(blockHash, c.blockView(block), err)
 
 		//	/block/<block-num>
 	case c.matchPath(rePathBlockNum):
 		num, _ := strconv.ParseUint(c.uriParts[1], 10, 64)
-		c.WriteVar(c.bc.GetBlock(num))
+		if c.exists("max_txs") || c.exists("max_bytes") {
+			c.WriteVar(c.truncatedBlock(c.getBlock(num)))
+			return
+		}
+		v, err := c.shared(func() (interface{}, error) {
+			return c.bc.GetBlock(num)
+		})
+		var blockHash []byte
+		block, _ := v.(*chain.Block)
+		if block != nil {
+			blockHash = block.Hash()
+		}
+		c.writeImmutable(blockHash, c.blockView(block), err)
+
+		//	/block/<block-num>/txs [?address=<address>]
+	case c.matchPath(rePathBlockTxs):
+		num, _ := strconv.ParseUint(c.uriParts[1], 10, 64)
+		c.WriteVar(c.blockTxs(num))
+
+		//	/block/<block-num>/overview
+	case c.matchPath(rePathBlockOvw):
+		num, _ := strconv.ParseUint(c.uriParts[1], 10, 64)
+		c.WriteVar(c.blockOverview(num))
+
+		//	/block/<block-num>/fee-split
+	case c.matchPath(rePathBlockFees):
+		num, _ := strconv.ParseUint(c.uriParts[1], 10, 64)
+		c.WriteVar(c.blockFeeSplit(num))
+
+		//	/block/<block-num>/finality
+	case c.matchPath(rePathBlockFinal):
+		num, _ := strconv.ParseUint(c.uriParts[1], 10, 64)
+		c.WriteVar(c.blockFinality(num))
 
 		//	/blocks?offset=<block-num>&limit=<count-blocks>
+		//	/blocks?cursor=<cursor>&limit=<count-blocks>  (empty cursor for the first page)
 	case c.uriPath == "/blocks":
-		offset := c.getUint("offset")
+		offset := c.getOffset()
 		limit := c.getLimit()
 		orderDesc := c.getOrderDesc()
-		c.WriteVar(c.bc.GetBlocks(offset, limit, orderDesc))
+		blocks, err := c.runQuery(func() (interface{}, error) {
+			return c.shared(func() (interface{}, error) {
+				return c.bc.GetBlocks(offset, limit, orderDesc)
+			}, "offset", "cursor", "limit", "order")
+		})
+		total := c.blocksCount()
+		if !c.exists("cursor") || err != nil {
+			c.rw.Header().Set("X-Total-Count", strconv.FormatUint(total, 10))
+			c.WriteVar(blocks, err)
+			return
+		}
+		c.WriteVar(c.blocksPage(blocks.([]*chain.Block), limit, orderDesc).setTotal(total))
+
+		//	/blocks/summary?count=<count-blocks>
+	case c.uriPath == "/blocks/summary":
+		c.WriteVar(c.blocksSummary())
+
+		//	/headers/verify  (body: binary-encoded list of block headers)
+	case c.uriPath == "/headers/verify":
+		c.WriteVar(c.verifyHeaders())
+
+		//	/merkle-root?hashes=<hash:hex>,<hash:hex>,...
+	case c.uriPath == "/merkle-root":
+		c.WriteVar(c.merkleRoot())
+
+		//	/confirmations/recommended?amount=<num>
+	case c.uriPath == "/confirmations/recommended":
+		c.WriteVar(c.recommendedConfirmations())
+
+		//	/validators/production?window=<count-blocks>
+	case c.uriPath == "/validators/production":
+		c.WriteVar(c.validatorsProduction())
+
+		//	/genesis/allocation?offset=<num>&limit=<num>
+	case c.uriPath == "/genesis/allocation":
+		c.WriteVar(c.genesisAllocation())
+
+		//	/network/throughput?window=<count-blocks>
+	case c.uriPath == "/network/throughput":
+		c.WriteVar(c.networkThroughput())
+
+	case c.uriPath == "/consensus/epoch":
+		c.WriteVar(c.consensusEpoch())
+
+		//	/tx/by-ref?ref=<memo|comment>&address=<recipient>
+	case c.uriPath == "/tx/by-ref":
+		c.WriteVar(c.txsByRef())
 
 		//	/tx/<hash:hex>
 	case c.matchPath(reTxHash):
 		txHash, _ := hex.DecodeString(c.uriParts[1])
-		c.WriteVar(c.bc.TransactionByHash(txHash))
+		tx, err := c.bc.TransactionByHash(txHash)
+		c.writeTx(tx, err)
+
+		//	/tx/<hash:hex>/confirmations
+	case c.matchPath(reTxConfirms):
+		txHash, _ := hex.DecodeString(c.uriParts[1])
+		c.WriteVar(c.txConfirmations(txHash))
+
+		//	/tx/<hash:hex>/output/<index>/spent-by
+	case c.matchPath(reTxOutSpentBy):
+		c.WriteVar(c.spentBy())
 
 		//	/tx/<txID:hex>
 	case c.matchPath(reTxID):
-		txID, _ := strconv.ParseUint(c.uriParts[1], 16, 64)
-		c.WriteVar(c.bc.TransactionByID(txID))
+		txID, err := parseTxID(c.uriParts[1])
+		c.assert(err)
+		c.writeTx(c.bc.TransactionByID(txID))
 
 		//	/address/?address=MDC&memo=...
 	case c.uriPath == "/address":
 		addr, memo := c.getAddress("")
-		c.WriteVar(c.bc.AddressInfo(addr, memo, assets.MDC))
+		c.writeAddressInfo(c.getAsset("asset"), addr, memo)
 
 		//	/address/MDCxxxxxxxxxxxxx
 	case c.matchPath(rePathAddressInfo):
 		addr, memo := c.getAddress(c.uriParts[1])
-		c.WriteVar(c.bc.AddressInfo(addr, memo, assets.MDC))
+		c.writeAddressInfo(c.getAsset("asset"), addr, memo)
+
+		//	/balance?address=<address>&asset=<asset>
+	case c.uriPath == "/balance":
+		c.WriteVar(c.balance())
+
+		//	/address/MDCxxxxxxxxxxxxx/txs?offset=<offset>&limit=<limit>&order=asc|desc&asset=<asset>
+	case c.matchPath(rePathAddrTxs):
+		c.WriteVar(c.addressTxs(c.getAddress(c.uriParts[1])))
+
+		//	/address/MDCxxxxxxxxxxxxx/stuck?min_age_seconds=<sec>
+	case c.matchPath(rePathAddrStuck):
+		addr, _ := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.stuckTxs(addr))
+
+		//	/address/MDCxxxxxxxxxxxxx/payment-uri?amount=<num>&memo=<num|hex>
+	case c.matchPath(rePathAddrPayURI):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.paymentURI(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/balance-proof
+	case c.matchPath(rePathAddrProof):
+		c.balanceProof()
+
+		//	/address/MDCxxxxxxxxxxxxx/activity?from=<time>&to=<time>&interval=day|week
+	case c.matchPath(rePathAddrActs):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.addressActivity(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/balance-breakdown
+	case c.matchPath(rePathAddrBalBrk):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.balanceBreakdown(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/total-value?in=<currency>
+	case c.matchPath(rePathAddrValue):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.totalValue(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/delta?from=<block-num>
+	case c.matchPath(rePathAddrDelta):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.addressDelta(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/name
+	case c.matchPath(rePathAddrName):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.addressName(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/balances?offset=<num>&limit=<num>
+	case c.matchPath(rePathAddrBals):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.balances(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/balances-at?heights=<num>,<num>,...
+	case c.matchPath(rePathAddrBalsAt):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.balancesAt(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/funding-source
+	case c.matchPath(rePathAddrFunding):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.fundingSource(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/payees?from=<time>&to=<time>&asset=<asset>
+	case c.matchPath(rePathAddrPayees):
+		addr, memo := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.payees(addr, memo))
+
+		//	/address/MDCxxxxxxxxxxxxx/managed-assets
+	case c.matchPath(rePathAddrAssets):
+		addr, _ := c.getAddress(c.uriParts[1])
+		c.WriteVar(c.managedAssets(addr))
+
+		//	/search?q=<block-num|tx-hash|address|tx-id>
+	case c.uriPath == "/search":
+		c.WriteVar(c.search())
+
+		//	/resolve?name=@<nick>
+	case c.uriPath == "/resolve":
+		c.WriteVar(c.resolveName())
+
+		//	/user/<userID>/upline?depth=<num>
+	case c.matchPath(rePathUserUpline):
+		userID, _ := strconv.ParseUint(c.uriParts[1], 0, 64)
+		c.WriteVar(c.upline(userID))
+
+		//	/user/<userID>/creation-tx
+	case c.matchPath(rePathUserCreated):
+		userID, _ := strconv.ParseUint(c.uriParts[1], 0, 64)
+		c.WriteVar(c.userCreationTx(userID))
+
+		//	/asset/<asset:hex>/creation-tx
+	case c.matchPath(rePathAssetCrtd):
+		asset, err := hex.DecodeString(c.uriParts[1])
+		c.assert(err)
+		c.WriteVar(c.assetCreationTx(asset))
+
+	case c.uriPath == "/portfolio":
+		c.WriteVar(c.portfolio())
 
 	case c.uriPath == "/txs":
-		addr, memo := c.getAddress("")
-		offset := c.getUint("offset")
-		limit := c.getLimit()
-		orderDesc := c.getOrderDesc()
-		txs, ofst, err := c.bc.TransactionsByAddr(assets.MDC, addr, memo, offset, limit, orderDesc)
-		c.WriteVar(NewResponse(txs, ofst, err))
+		c.WriteVar(c.addressTxs(c.getAddress("")))
+
+		//	/txs/simulate?txs=<tx:hex>,<tx:hex>,...  (or body: binary-encoded list of transactions)
+	case c.uriPath == "/txs/simulate":
+		c.WriteVar(c.simulateTxs())
+
+		//	/txs/batch?txs=<hash|id>,<hash|id>,...
+	case c.uriPath == "/txs/batch":
+		c.WriteVar(c.txsBatch())
+
+		//	/txs/stream?address=<address>&memo=<memo>  (Server-Sent Events)
+	case c.uriPath == "/txs/stream":
+		c.streamAddressTxs()
+
+		//	/txs/filtered?filter=<bits:hex>&hash_funcs=<n>&tweak=<n>&from=<block-num>&to=<block-num>  (or body: bits, hash_funcs, tweak)
+	case c.uriPath == "/txs/filtered":
+		c.WriteVar(c.filteredTxs())
+
+		//	/txs/export?address=<address>&format=csv|ofx
+	case c.uriPath == "/txs/export":
+		c.exportTxs()
+
+		//	/mempool...  (mempool of node can't be enumerated)
+	case c.uriPath == "/mempool", c.uriPath == "/mempool/size", c.uriPath == "/mempool/sorted",
+		c.uriPath == "/mempool/dependencies", c.matchPath(rePathMempoolPkg):
+		c.abort(errMempoolNotEnumerable, http.StatusNotImplemented)
+
+	case c.uriPath == "/mempool/check":
+		c.WriteVar(c.mempoolCheck(c.getTx()))
+
+		//	/submitted?offset=<num>&limit=<num>&order=asc|desc
+	case c.uriPath == "/submitted":
+		c.writeMutable(c.submittedTxsPage())
 
+	case c.uriPath == "/submitted/size":
+		c.writeMutable(c.submittedSize())
+
+		//	/submitted/sorted?order=fee_desc|fee_asc&offset=<num>&limit=<num>
+	case c.uriPath == "/submitted/sorted":
+		c.writeMutable(c.sortedSubmitted())
+
+		//	/submitted/<hash:hex>/package
+	case c.matchPath(rePathSubmitPkg):
+		txHash, _ := hex.DecodeString(c.uriParts[1])
+		c.WriteVar(c.submittedPackage(txHash))
+
+	case c.uriPath == "/submitted/dependencies":
+		c.writeMutable(mempoolDependencies(c.bc.Cfg, c.submittedPending()))
+
+	case c.uriPath == "/ws/sync":
+		c.serveSync()
+
+	case c.uriPath == "/ws/blocks":
+		c.serveBlocks()
+
+		//	/put-tx
 	case c.uriPath == "/put-tx":
-		var tx *chain.Transaction
-		c.getBinary(&tx)
-		err := c.bc.Mempool.Put(tx)
+		c.assertNoSubmitDeadline()
+		tx := c.getSubmittedTx()
+		c.assertTx(tx.Verify(c.bc.Cfg))
+		c.assertFeeRate(tx)
+		err := c.putTx(tx)
 		c.WriteVar(0, err)
 
+	case c.uriPath == "/sign-and-submit":
+		tx := c.getTx()          // unsigned transaction (hex-param "tx" OR binary body)
+		prv := c.getPrivateKey() // private key OR seed
+		tx.Sign(prv)
+		c.assertTx(tx.Verify(c.bc.Cfg))
+		c.assertFeeRate(tx)
+
+		err := c.putTx(tx)
+		c.WriteVar(tx, err)
+
+		//	/fee-rates
+	case c.uriPath == "/fee-rates":
+		c.WriteVar(c.feeRates())
+
+		//	/estimate-fee?address=<address>&amount=<num>&asset=<asset>
+	case c.uriPath == "/estimate-fee":
+		c.WriteVar(c.estimateFee())
+
 	case c.uriPath == "/new-transfer":
 		prvKey := c.getPrivateKey()        // private key OR seed
 		toAddr, toMemo := c.getAddress("") // address
@@ -130,13 +480,31 @@ func (c *Context) Exec() {
 		comment := c.getStr("comment", "") // comment (by default "")
 		nonce := c.getNonce()              // nonce (by default 0)
 		asset := assets.MDC                //
+		c.assertNoSubmitDeadline()
 
+		if comment != "" && c.getBool("encrypt_comment") {
+			comment = c.encryptComment(comment, toAddr)
+		}
 		tx := txobj.NewSimpleTransfer(c.bc, prvKey, asset, amount, 0, toAddr, toMemo, comment, nonce)
-		c.assert(tx.Verify(c.bc.Cfg))
+		c.assertTx(tx.Verify(c.bc.Cfg))
+		c.assertFeeRate(tx)
 
-		err := c.bc.Mempool.Put(tx)
+		if c.getBool("dry_run") { // signed transaction isn't put to mempool
+			c.writeUnsubmittedTx(tx)
+			return
+		}
+		err := c.putTx(tx)
 		c.WriteVar(tx, err)
 
+		//	/new-multi-transfer?outputs=[{"address":<address>,"memo":<num|hex>,"amount":<num>},...]
+	case c.uriPath == "/new-multi-transfer":
+		tx := c.newMultiTransfer()
+		if c.getBool("dry_run") {
+			c.writeUnsubmittedTx(tx)
+			return
+		}
+		c.WriteVar(tx, c.putTx(tx))
+
 	case c.uriPath == "/new-user":
 		prv := c.getPrivateKey()          // private key OR seed
 		nick := c.getStr("login", "")     // user nickname
@@ -153,11 +521,20 @@ func (c *Context) Exec() {
 			return
 		}
 		tx := txobj.NewUser(c.bc, prv, nick, referrerID)
-		c.assert(tx.Verify(c.bc.Cfg))
+		c.assertTx(tx.Verify(c.bc.Cfg))
 
-		err = c.bc.Mempool.Put(tx)
+		err = c.putTx(tx)
 		c.WriteVar(tx, err)
 
+	case c.uriPath == "/admin/reload":
+		c.assertAdmin()
+		c.WriteVar(c.adminReload())
+
+		//	/admin/rejections?limit=<count>
+	case c.uriPath == "/admin/rejections":
+		c.assertAdmin()
+		c.WriteVar(c.rejections.last(int(c.getLimit())))
+
 	case c.uriPath == "/new-key":
 		prv := c.getPrivateKey() // private key OR seed
 		c.WriteVar(struct {
@@ -173,42 +550,152 @@ func (c *Context) Exec() {
 		})
 
 	default:
+		c.route = "other"
 		c.WriteError(err404, http.StatusNotFound)
 	}
 
 	return
 }
 
+type addressInfoExt struct {
+	*chain.AddressInfo
+	ConfirmedHeight  *uint64           `json:"confirmed_height,omitempty"`
+	ConfirmedBalance *bignum.Int       `json:"confirmed_balance,omitempty"` // balance as of confirmed height
+	Encodings        *addressEncodings `json:"encodings,omitempty"`
+}
+
+// writeTx writes transaction of blockchain (confirmed transactions are immutable)
+func (c *Context) writeTx(tx *chain.Transaction, err error) {
+	if err == nil && tx == nil {
+		err = errTxNotFound
+	}
+	var txHash []byte
+	if tx != nil && c.confirmations(tx) > 0 {
+		txHash = tx.Hash()
+	}
+	c.writeImmutable(txHash, tx, err)
+}
+
+func (c *Context) writeAddressInfo(asset, addr []byte, memo uint64) {
+	info, err := c.bc.AddressInfo(addr, memo, asset)
+	if err != nil {
+		c.WriteVar(nil, err)
+		return
+	}
+	var v interface{} = info
+	if c.withEncodings() || c.exists("min_confirmations") {
+		ext := &addressInfoExt{AddressInfo: info}
+		if c.exists("min_confirmations") {
+			height := c.confirmedHeight(c.getUint("min_confirmations"))
+			balance := c.balanceAt(asset, addr, memo, height)
+			ext.ConfirmedHeight, ext.ConfirmedBalance = &height, &balance
+		}
+		if c.withEncodings() {
+			ext.Encodings = c.addressEncodings(addr, memo)
+		}
+		v = ext
+	}
+	if c.notModified(c.etag(v)) {
+		return
+	}
+	c.WriteVar(v)
+}
+
 //----------------------- request --------------------------------------
 func (c *Context) matchPath(re *regexp.Regexp) bool {
 	c.uriParts = re.FindStringSubmatch(c.uriPath)
-	return len(c.uriParts) > 0
+	if len(c.uriParts) == 0 {
+		return false
+	}
+	c.route = routeLabel(re)
+	return true
 }
 
 func (c *Context) assert(err error) {
 	if err != nil {
-		c.WriteError(err, 400)
-		panic(err)
+		code, ok := errorStatus(err)
+		if !ok {
+			code = http.StatusBadRequest
+		}
+		c.abort(err, code)
+	}
+}
+
+// assertFeeRate rejects transaction with fee rate below configured minimum.
+// Fee of transaction is fixed (cfg.TxFee), so minimal fee rate is a cap of transaction size
+func (c *Context) assertFeeRate(tx *chain.Transaction) {
+	c.assertTx(c.checkFeeRate(tx))
+}
+
+func (c *Context) checkFeeRate(tx *chain.Transaction) error {
+	if c.cfg.MinFeeRate <= 0 {
+		return nil
+	}
+	size := txSize(tx)
+	fee, minFee := txFee(c.bc.Cfg, tx), bignum.NewInt(c.cfg.MinFeeRate*size)
+	if fee.Cmp(minFee) < 0 {
+		return fmt.Errorf("400 - Transaction size %d bytes is too large: fixed fee %v is below minimum %v (%d per byte). Reduce the transaction (e.g. shorten comment)", size, fee, minFee, c.cfg.MinFeeRate)
+	}
+	return nil
+}
+
+// assertFound aborts request with 404-response if object is not found
+func (c *Context) assertFound(found bool) {
+	if !found {
+		c.abort(err404, http.StatusNotFound)
 	}
 }
 
 func (c *Context) exists(name string) bool {
-	_, ok := c.reqQuery[name]
-	return ok
+	_, inBody := c.bodyParams[name]
+	_, inQuery := c.reqQuery[name]
+	return inBody || inQuery
 }
 
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// getLimit returns page size of list by param "limit" (Server.DefaultLimit by default).
+// Limit above Server.MaxLimit is clamped and response gets header "X-Limit-Clamped: true".
 func (c *Context) getLimit() (limit int64) {
+	def, max := c.DefaultLimit, c.MaxLimit
+	if def <= 0 {
+		def = defaultLimit
+	}
+	if max <= 0 {
+		max = maxLimit
+	}
+	if def > max {
+		def = max
+	}
 	limit = c.getInt("limit")
 	if limit <= 0 {
-		limit = 20
-	} else if limit > 100 {
-		limit = 100
+		limit = def
+	} else if limit > max {
+		limit = max
+		c.rw.Header().Set("X-Limit-Clamped", "true")
 	}
 	return
 }
 
+// getAsset returns asset id by param given as hex-encoded id or symbol (assets.MDC by default).
+// Unknown symbols, malformed ids and assets which are not issued in blockchain are rejected with 400.
+func (c *Context) getAsset(name string) []byte {
+	s := c.getStr(name, "MDC")
+	if asset, ok := c.assetBySymbol(s); ok {
+		return asset
+	}
+	asset, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(asset) == 0 || !c.assetExists(asset) {
+		c.assert(errUnknownAsset)
+	}
+	return asset
+}
+
 func (c *Context) getAddress(defaultValue string) (addr []byte, memo uint64) {
-	addr, memo, err := c.bc.AddressByStr(c.getStr("address", defaultValue))
+	addr, memo, err := c.addressByStr(c.getStr("address", defaultValue))
 	c.assert(err)
 	if s := c.getStr("memo", ""); s != "" {
 		memo, err = strconv.ParseUint(s, 0, 64)
@@ -233,12 +720,6 @@ func (c *Context) getOrderDesc() bool {
 	return c.getStr("order", "asc") == "desc"
 }
 
-func (c *Context) getAmount(name string) (n bignum.Int) {
-	v, err := strconv.ParseUint(c.getStr(name, "0"), 10, 64)
-	c.assert(err)
-	return bignum.NewInt(int64(v))
-}
-
 func (c *Context) getNonce() (n uint64) {
 	n, err := strconv.ParseUint(c.getStr("nonce", "0"), 0, 64)
 	c.assert(err)
@@ -246,7 +727,7 @@ func (c *Context) getNonce() (n uint64) {
 }
 
 func (c *Context) getStr(name, defaultValue string) string {
-	if v := c.reqQuery.Get(name); v != "" {
+	if v := c.param(name); v != "" {
 		return v
 	}
 	return defaultValue
@@ -258,63 +739,176 @@ func (c *Context) getInt(name string) int64 {
 	return n
 }
 
+// getBool returns flag param: bare param (e.g. "&dry_run") is true, value is parsed by strconv.ParseBool
+func (c *Context) getBool(name string) bool {
+	s := c.getStr(name, "")
+	if s == "" {
+		return c.exists(name)
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		c.assert(fmt.Errorf("400 - Invalid param %s: %q is not a boolean", name, s))
+	}
+	return v
+}
+
 func (c *Context) getUint(name string) uint64 {
 	n, err := strconv.ParseUint(c.getStr(name, "0"), 0, 64)
 	c.assert(err)
 	return n
 }
 
+// getTx returns transaction passed as hex-encoded param "tx" or as binary request body
+func (c *Context) getTx() (tx *chain.Transaction) {
+	if s := c.getStr("tx", ""); s != "" {
+		data, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		c.assert(err)
+		c.assert(bin.Read(bytes.NewReader(data), &tx))
+	} else {
+		c.getBinary(&tx)
+	}
+	if tx == nil {
+		c.assert(errors.New("400 - Empty transaction"))
+	}
+	return
+}
+
+var errUnsupportedMediaType = errors.New("415 - Unsupported content type (expected application/json or binary)")
+
+// getSubmittedTx returns transaction of request body encoded as JSON (Content-Type: application/json) or binary
+func (c *Context) getSubmittedTx() (tx *chain.Transaction) {
+	switch ct, _, _ := mime.ParseMediaType(c.req.Header.Get("Content-Type")); ct {
+	case "application/json":
+		data, err := io.ReadAll(c.req.Body)
+		c.assert(err)
+		if err := json.Unmarshal(data, &tx); err != nil {
+			c.assert(fmt.Errorf("400 - Invalid JSON transaction: %v", err))
+		}
+	case "", contentTypeBinary, contentTypeOctetStream, contentTypeVendorBinary:
+		c.getBinary(&tx)
+	default:
+		c.abort(errUnsupportedMediaType, http.StatusUnsupportedMediaType)
+	}
+	if tx == nil {
+		c.assert(errors.New("400 - Empty transaction"))
+	}
+	return
+}
+
+// getBinary decodes binary-encoded request body.
+// Body is read in full (up to max body size, 413 if it's larger) before decoding,
+// so length prefixes of encoded values can't claim more data than client has sent.
 func (c *Context) getBinary(v ...interface{}) {
-	err := c.reqBody.ReadVar(v...)
+	data, err := io.ReadAll(c.req.Body)
 	c.assert(err)
+	c.assert(bin.Read(bytes.NewReader(data), v...))
 }
 
 //----------------------- response -------------------------------------
+// WriteError writes error response (httpCode 0 - status by error, see statusForError)
 func (c *Context) WriteError(err error, httpCode int) {
-	xlog.Error.Printf("rest> Response-ERROR-%d: %v", httpCode, err)
+	if httpCode == 0 {
+		httpCode = statusForError(err)
+	}
+	xlog.Error.Printf("rest> [%s] Response-ERROR-%d: %v", c.reqID, httpCode, err)
 
 	var buf io.Reader
-	if c.req.Header.Get("Accept") == contentTypeBinary {
-		c.rw.Header().Set("Content-Type", contentTypeBinary)
+	if c.wantsBinary() {
+		c.rw.Header().Set("Content-Type", c.binaryContentType())
 		buf = bytes.NewBufferString(err.Error())
+	} else if c.wantsProtobuf() {
+		c.rw.Header().Set("Content-Type", contentTypeProtobuf)
+		buf = bytes.NewBuffer(protoResponse(nil, err.Error()))
 	} else {
 		c.rw.Header().Set("Content-Type", contentTypeJSON)
 		data, _ := json.Marshal(&Response{Error: err.Error()})
 		buf = bytes.NewBuffer(data)
 	}
 	c.rw.Header().Set("X-Content-Type-Options", "nosniff")
-	c.rw.WriteHeader(httpCode)
-	io.Copy(c.rw, buf)
+	c.writeBody(httpCode, buf)
+}
+
+// writeHead writes headers of response to HEAD-request; body isn't encoded
+func (c *Context) writeHead() {
+	contentType := contentTypeJSON
+	if c.wantsNDJSON() {
+		contentType = contentTypeNDJSON
+	} else if c.wantsBinary() {
+		contentType = c.binaryContentType()
+	} else if c.wantsProtobuf() {
+		contentType = contentTypeProtobuf
+	}
+	c.rw.Header().Set("Content-Type", contentType)
+	httpCode := c.httpStatus
+	if httpCode == 0 {
+		httpCode = http.StatusOK
+	}
+	c.writeHeader(httpCode)
 }
 
 func (c *Context) WriteVar(v interface{}, ee ...error) {
 	if len(ee) > 0 && ee[0] != nil { // error
-		c.WriteError(ee[0], 500)
+		c.WriteError(ee[0], 0)
+		return
+	}
+	if r, ok := v.(*Response); ok && (c.req.Method == "HEAD" || c.wantsBinary() && !c.wantsNDJSON()) {
+		// pagination metadata of responses without JSON body
+		c.rw.Header().Set("X-Next-Offset", r.NextOffset)
+		if r.Total != nil {
+			c.rw.Header().Set("X-Total-Count", strconv.FormatUint(*r.Total, 10))
+		}
+		if r.NextCursor != "" {
+			c.rw.Header().Set("X-Next-Cursor", r.NextCursor)
+		}
+	}
+	if c.req.Method == "HEAD" {
+		c.writeHead()
+		return
+	}
+	if c.wantsNDJSON() {
+		c.writeNDJSON(v)
 		return
 	}
 	var buf io.Reader
-	if c.req.Header.Get("Accept") == contentTypeBinary {
+	if c.wantsBinary() {
 		// binary-response
-		c.rw.Header().Set("Content-Type", contentTypeBinary)
+		c.rw.Header().Set("Content-Type", c.binaryContentType())
 		if r, ok := v.(*Response); ok {
 			v = r.Results
-			c.rw.Header().Set("X-Next-Offset", r.NextOffset)
 		}
 		buf = bin.NewBuffer(nil, v)
 
+	} else if c.wantsProtobuf() {
+		// protobuf-response
+		c.rw.Header().Set("Content-Type", contentTypeProtobuf)
+		buf = bytes.NewBuffer(protoResponse(v, ""))
+
 	} else {
 		// json-response
 		c.rw.Header().Set("Content-Type", contentTypeJSON)
+		if r, ok := v.(*Response); ok && c.deprecation != nil {
+			rr := *r
+			rr.Deprecation = c.deprecation
+			v = &rr
+		}
+		if fields := c.projection(); fields != nil {
+			v = project(v, fields)
+		}
+		if c.wantsISOTime() {
+			v = isoTimestamps(v)
+		}
+		if c.wantsCamelCase() {
+			v = camelCaseKeys(v)
+		}
 		var data []byte
-		if _, ok := c.reqQuery["pretty"]; ok {
+		if c.exists("pretty") {
 			data, _ = json.MarshalIndent(v, "", "  ")
 		} else {
 			data, _ = json.Marshal(v)
 		}
 		buf = bytes.NewBuffer(data)
 	}
-	_, err := io.Copy(c.rw, buf)
-	if err != nil {
-		xlog.Error.Printf("rest> http-response-error: %v", err)
+	if err := c.writeBody(c.httpStatus, buf); err != nil {
+		xlog.Error.Printf("rest> [%s] http-response-error: %v", c.reqID, err)
 	}
 }
@@ -0,0 +1,64 @@
+package restsrv
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORSPolicy is policy of cross-origin requests of browsers
+type CORSPolicy struct {
+	Origins      []string // origins allowed to call read endpoints ("*" - any origin)
+	WriteOrigins []string // origins allowed to call POST, PUT endpoints (empty - writes are refused to any origin)
+}
+
+const corsAllowMethods = "GET, HEAD, POST, PUT, OPTIONS"
+
+// allowedOrigin returns value of header Access-Control-Allow-Origin for request of origin with method ("" - not allowed)
+func (p *CORSPolicy) allowedOrigin(origin, method string) string {
+	origins := p.Origins
+	if method == "POST" || method == "PUT" {
+		origins = p.WriteOrigins
+	}
+	for _, o := range origins {
+		if o == "*" {
+			return "*"
+		}
+		if strings.EqualFold(o, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// serveCORS sets CORS-headers of response and answers preflight request (OPTIONS) with 204-response
+func (c *Context) serveCORS() (preflight bool) {
+	origin := c.req.Header.Get("Origin")
+	if origin == "" || c.CORS == nil {
+		return false
+	}
+	method := c.req.Method
+	if reqMethod := c.req.Header.Get("Access-Control-Request-Method"); method == "OPTIONS" && reqMethod != "" {
+		method, preflight = reqMethod, true
+	}
+	h := c.rw.Header()
+	h.Add("Vary", "Origin")
+	if allowed := c.CORS.allowedOrigin(origin, method); allowed != "" {
+		allowHeaders := "Accept, Content-Type, Authorization, X-API-Key, TE, If-None-Match, If-Modified-Since, Idempotency-Key"
+		exposeHeaders := "X-Next-Offset, X-Next-Cursor, X-Total-Count, X-Limit-Clamped, X-Truncated, ETag, Warning, Deprecation, Sunset, Retry-After, Idempotent-Replayed"
+		if c.cfg.RequestIDHeader != "" {
+			allowHeaders += ", " + c.cfg.RequestIDHeader
+			exposeHeaders += ", " + c.cfg.RequestIDHeader
+		}
+		h.Set("Access-Control-Allow-Origin", allowed)
+		h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+		h.Set("Access-Control-Allow-Headers", allowHeaders)
+		h.Set("Access-Control-Expose-Headers", exposeHeaders)
+		if preflight {
+			h.Set("Access-Control-Max-Age", "600")
+		}
+	}
+	if preflight {
+		c.writeHeader(http.StatusNoContent)
+	}
+	return
+}
@@ -0,0 +1,39 @@
+package restsrv
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSPolicy_allowedOrigin(t *testing.T) {
+	p := &CORSPolicy{Origins: []string{"*"}}
+
+	assert.Equal(t, "*", p.allowedOrigin("https://example.com", "GET"))
+	assert.Equal(t, "", p.allowedOrigin("https://example.com", "POST"))
+	assert.Equal(t, "", p.allowedOrigin("https://example.com", "PUT"))
+}
+
+func TestCORSPolicy_allowedOrigin_WriteOrigins(t *testing.T) {
+	p := &CORSPolicy{Origins: []string{"*"}, WriteOrigins: []string{"https://wallet.example.com"}}
+
+	assert.Equal(t, "https://Wallet.example.com", p.allowedOrigin("https://Wallet.example.com", "POST"))
+	assert.Equal(t, "", p.allowedOrigin("https://example.com", "POST"))
+}
+
+func TestContext_serveCORS_Headers(t *testing.T) {
+	req := httptest.NewRequest("OPTIONS", "/new-transfer", nil)
+	req.Header.Set("Origin", "https://wallet.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	c, rw := newTestContext(req)
+	c.CORS = &CORSPolicy{WriteOrigins: []string{"https://wallet.example.com"}}
+
+	preflight := c.serveCORS()
+
+	assert.True(t, preflight)
+	assert.Contains(t, rw.Header().Get("Access-Control-Allow-Headers"), "Idempotency-Key")
+	assert.Contains(t, rw.Header().Get("Access-Control-Allow-Headers"), "If-Modified-Since")
+	assert.Contains(t, rw.Header().Get("Access-Control-Expose-Headers"), "Retry-After")
+	assert.Contains(t, rw.Header().Get("Access-Control-Expose-Headers"), "Idempotent-Replayed")
+}
@@ -0,0 +1,82 @@
+package restsrv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var errInvalidCursor = errors.New("400 - Invalid or expired cursor. Restart pagination from the first page")
+
+// newCursorCipher returns AEAD-cipher for pagination cursors.
+// Random key is used when secret is empty, so cursors become invalid after node restart.
+func newCursorCipher(secret string) cipher.AEAD {
+	var key [32]byte
+	if secret != "" {
+		key = sha256.Sum256([]byte(secret))
+	} else if _, err := rand.Read(key[:]); err != nil {
+		panic(err)
+	}
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		panic(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return aead
+}
+
+// sealCursor encrypts and signs cursor payload, so clients can't read or craft cursors
+func (c *Context) sealCursor(payload []byte) string {
+	plain := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint64(plain, uint64(time.Now().Add(c.cfg.CursorTTL).Unix()))
+	plain = append(plain, payload...)
+
+	nonce := make([]byte, c.cursorCipher.NonceSize())
+	rand.Read(nonce)
+	return base64.RawURLEncoding.EncodeToString(c.cursorCipher.Seal(nonce, nonce, plain, nil))
+}
+
+// openCursor returns payload of cursor sealed by sealCursor
+func (c *Context) openCursor(cursor string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	nonceSize := c.cursorCipher.NonceSize()
+	if err != nil || len(data) < nonceSize {
+		return nil, errInvalidCursor
+	}
+	plain, err := c.cursorCipher.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil || len(plain) < 8 {
+		return nil, errInvalidCursor
+	}
+	if time.Now().Unix() > int64(binary.BigEndian.Uint64(plain)) {
+		return nil, errInvalidCursor
+	}
+	return plain[8:], nil
+}
+
+func (c *Context) sealOffsetCursor(offset uint64) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], offset)
+	return c.sealCursor(buf[:])
+}
+
+// getOffset returns offset passed as opaque param "cursor" (takes precedence) or as param "offset"
+func (c *Context) getOffset() uint64 {
+	s := c.getStr("cursor", "")
+	if s == "" {
+		return c.getUint("offset")
+	}
+	payload, err := c.openCursor(s)
+	if err == nil && len(payload) != 8 {
+		err = errInvalidCursor
+	}
+	c.assert(err)
+	return binary.BigEndian.Uint64(payload)
+}
@@ -0,0 +1,17 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+)
+
+var errSubmitDeadline = errors.New("501 - Param submit_deadline is not supported: mempool of node can't drop pending transactions")
+
+// assertNoSubmitDeadline rejects param "submit_deadline" of writes with 501:
+// mempool of node has no removal of pending transactions and node doesn't rebroadcast them,
+// so there is nothing to stop at deadline and transaction can't be dropped
+func (c *Context) assertNoSubmitDeadline() {
+	if c.exists("submit_deadline") {
+		c.abort(errSubmitDeadline, http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,17 @@
+package restsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_assertNoSubmitDeadline(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("POST", "/put-tx?submit_deadline=1700000000", nil))
+
+	assert.Panics(t, c.assertNoSubmitDeadline)
+
+	assert.Equal(t, http.StatusNotImplemented, rw.Code)
+}
@@ -0,0 +1,71 @@
+package restsrv
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Deprecation marks route or param of route as deprecated
+type Deprecation struct {
+	Path        string    // request path; "/prefix/*" matches all paths with prefix
+	Param       string    // deprecated param of route ("" - whole route is deprecated)
+	Sunset      time.Time // date of removal (optional)
+	Replacement string    // recommended replacement (optional)
+}
+
+func (d *Deprecation) matches(c *Context) bool {
+	if strings.HasSuffix(d.Path, "/*") {
+		if !strings.HasPrefix(c.uriPath+"/", strings.TrimSuffix(d.Path, "*")) {
+			return false
+		}
+	} else if d.Path != c.uriPath {
+		return false
+	}
+	return d.Param == "" || c.exists(d.Param)
+}
+
+type deprecationNotice struct {
+	Message     string `json:"message"`
+	Sunset      string `json:"sunset,omitempty"` // date in format YYYY-MM-DD
+	Replacement string `json:"replacement,omitempty"`
+}
+
+func (d *Deprecation) notice(path string) *deprecationNotice {
+	n := &deprecationNotice{Replacement: d.Replacement}
+	if d.Param != "" {
+		n.Message = fmt.Sprintf("Param %q of %s is deprecated", d.Param, path)
+	} else {
+		n.Message = fmt.Sprintf("%s is deprecated", path)
+	}
+	if !d.Sunset.IsZero() {
+		n.Sunset = d.Sunset.UTC().Format("2006-01-02")
+	}
+	return n
+}
+
+// checkDeprecations sets headers Warning, Deprecation and Sunset if request uses deprecated route or param
+func (c *Context) checkDeprecations() {
+	for _, d := range c.Deprecations {
+		if !d.matches(c) {
+			continue
+		}
+		n := d.notice(c.uriPath)
+		c.deprecation = n
+		text := n.Message
+		if n.Sunset != "" {
+			text += ", sunset " + n.Sunset
+		}
+		if n.Replacement != "" {
+			text += ", use " + n.Replacement
+		}
+		h := c.rw.Header()
+		h.Add("Warning", fmt.Sprintf("299 - %q", text))
+		h.Set("Deprecation", "true")
+		if !d.Sunset.IsZero() {
+			h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		return
+	}
+}
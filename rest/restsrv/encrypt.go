@@ -0,0 +1,50 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/crypto"
+)
+
+// encryptedCommentPrefix marks comment of transaction encrypted to public key of recipient: "ecies:<ciphertext:hex>".
+// Transactions are returned as is, so recipient decrypts the comment client-side by private key.
+const encryptedCommentPrefix = "ecies:"
+
+var errUnknownRecipientKey = errors.New("422 - Can't encrypt comment: public key of recipient is unknown (recipient hasn't sent transactions yet)")
+
+// recipientPublicKey returns public key of address: key of sender of transaction from address
+// (registration of user as well) or nil if address hasn't sent transactions.
+func (c *Context) recipientPublicKey(addr []byte) (pub *crypto.PublicKey) {
+	c.scanAddressTxs(assets.MDC, addr, 0, true, maxScanTxs, func(tx *chain.Transaction) bool {
+		if tx.Sender != nil && bytes.Equal(tx.Sender.Address(), addr) {
+			pub = tx.Sender
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// encryptComment returns comment encrypted to public key of recipient address
+func (c *Context) encryptComment(comment string, toAddr []byte) string {
+	pub := c.recipientPublicKey(toAddr)
+	if pub == nil {
+		c.assert(errUnknownRecipientKey)
+	}
+	s, err := encryptCommentTo(pub, comment)
+	c.assert(err)
+	return s
+}
+
+// encryptCommentTo returns comment encrypted by ECIES to public key as "ecies:<ciphertext:hex>"
+func encryptCommentTo(pub *crypto.PublicKey, comment string) (string, error) {
+	data, err := pub.Encrypt([]byte(comment))
+	if err != nil {
+		return "", err
+	}
+	return encryptedCommentPrefix + hex.EncodeToString(data), nil
+}
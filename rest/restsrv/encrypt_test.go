@@ -0,0 +1,25 @@
+package restsrv
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptCommentTo(t *testing.T) {
+	prv := crypto.NewPrivateKeyBySecret("test-recipient")
+
+	s, err := encryptCommentTo(prv.PublicKey(), "order #123")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(s, encryptedCommentPrefix), s)
+	data, err := hex.DecodeString(strings.TrimPrefix(s, encryptedCommentPrefix))
+	assert.NoError(t, err)
+	assert.NotEqual(t, "order #123", string(data))
+	plain, err := prv.Decrypt(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "order #123", string(plain))
+}
@@ -0,0 +1,41 @@
+package restsrv
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Blockchain doesn't classify its errors, so errors of transactions are classified by stage of processing:
+// failed verification (tx.Verify) - 400, rejection by mempool (e.g. insufficient balance or nonce) - 422,
+// transaction already stored in blockchain - 409.
+var errTxDuplicate = errors.New("409 - Transaction already exists")
+
+// txRejectedError returns error of mempool rejecting transaction as 422-error
+func txRejectedError(err error) error {
+	return fmt.Errorf("422 - Transaction is rejected by mempool: %w", err)
+}
+
+// errorStatus returns http-status of error of this package given as "<status> - <message>"
+func errorStatus(err error) (int, bool) {
+	msg := err.Error()
+	if len(msg) > 6 && msg[3:6] == " - " {
+		if code, e := strconv.Atoi(msg[:3]); e == nil && code >= 400 && code < 600 {
+			return code, true
+		}
+	}
+	var errTooLarge *http.MaxBytesError
+	if errors.As(err, &errTooLarge) {
+		return http.StatusRequestEntityTooLarge, true
+	}
+	return 0, false
+}
+
+// statusForError returns http-status of error (500 for unknown errors)
+func statusForError(err error) int {
+	if code, ok := errorStatus(err); ok {
+		return code
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,42 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusForError(t *testing.T) {
+	for err, status := range map[error]int{
+		errMethodNotAllowed: http.StatusMethodNotAllowed,
+		errTxDuplicate:      http.StatusConflict,
+		txRejectedError(errors.New("insufficient funds")): http.StatusUnprocessableEntity,
+		&http.MaxBytesError{Limit: 10}:                    http.StatusRequestEntityTooLarge,
+		errors.New("leveldb: invalid checksum of record"): http.StatusInternalServerError, // message of unknown error doesn't matter
+		errors.New("Transaction not found"):               http.StatusInternalServerError,
+	} {
+		assert.Equal(t, status, statusForError(err), err.Error())
+	}
+}
+
+func TestContext_putTx_MempoolRejection(t *testing.T) {
+	s := &Server{bc: newTestChain(t), submitted: newSubmittedTxs(), metrics: newMetrics(), rejections: newRejections(10)}
+	s.setConfig(&Config{})
+	c := newContext(s, httptest.NewRequest("POST", "/put-tx", nil), httptest.NewRecorder())
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().Address()
+	tx := txobj.NewSimpleTransfer(s.bc, crypto.NewPrivateKeyBySecret("sender"), assets.MDC, bignum.NewInt(assets.Coin), 0, to, 0, "", 0)
+	tx.Nonce++ // signature doesn't match transaction; sender of empty chain has no funds
+
+	err := c.putTx(tx)
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, statusForError(err))
+	assert.Equal(t, 0, len(s.submitted.m))
+}
@@ -0,0 +1,106 @@
+package restsrv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mediacoin-pro/core/common/bin"
+)
+
+// etag returns a strong entity tag of object v for the representation variant requested by client
+func (c *Context) etag(v interface{}) string {
+	h := sha256.New()
+	io.Copy(h, bin.NewBuffer(nil, v))
+	io.WriteString(h, c.reprVariant())
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// immutableETag returns a strong entity tag of immutable resource identified by id (hash of block or transaction)
+func (c *Context) immutableETag(id []byte) string {
+	h := sha256.New()
+	h.Write(id)
+	io.WriteString(h, c.reprVariant())
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// reprVariant returns name of response representation (content-type and formatting options)
+func (c *Context) reprVariant() string {
+	if c.wantsBinary() {
+		return "binary"
+	}
+	if c.wantsProtobuf() {
+		return "protobuf"
+	}
+	variant := "json"
+	if c.wantsNDJSON() {
+		variant = "ndjson"
+	} else {
+		if c.exists("pretty") {
+			variant += "-pretty"
+		}
+		if c.projection() != nil {
+			variant += "-profile:" + c.getStr("profile", "")
+		}
+	}
+	if c.wantsISOTime() {
+		variant += "-iso"
+	}
+	if c.wantsCamelCase() {
+		variant += "-camel"
+	}
+	return variant
+}
+
+// notModified sets ETag-header and writes 304-response if client already has actual version of resource
+func (c *Context) notModified(etag string) bool {
+	c.rw.Header().Set("ETag", etag)
+	c.rw.Header().Add("Vary", "Accept")
+	if matchETag(c.req.Header.Get("If-None-Match"), etag) {
+		c.writeHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+func matchETag(header, etag string) bool {
+	for _, s := range strings.Split(header, ",") {
+		if s = strings.TrimPrefix(strings.TrimSpace(s), "W/"); s == etag || s == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeImmutable writes response of immutable resource identified by id (nil - resource isn't found).
+// ETag of response is derived from id, so client having actual version (header If-None-Match) gets 304-response.
+func (c *Context) writeImmutable(id []byte, v interface{}, err error) {
+	if err == nil && id != nil && c.notModified(c.immutableETag(id)) {
+		return
+	}
+	c.WriteVar(v, err)
+}
+
+// writeModified writes response of resource modified at time t (e.g. timestamp of the last block).
+// Response has header Last-Modified; clients revalidate their copies by If-Modified-Since and get 304 if copy is actual.
+// Http-dates have precision of seconds, so changes within the same second as client's copy are seen with the next change.
+func (c *Context) writeModified(t time.Time, fn func() (interface{}, error)) {
+	t = t.UTC().Truncate(time.Second)
+	h := c.rw.Header()
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Last-Modified", t.Format(http.TimeFormat))
+	if since, err := http.ParseTime(c.req.Header.Get("If-Modified-Since")); err == nil && !t.After(since) {
+		c.writeHeader(http.StatusNotModified)
+		return
+	}
+	c.WriteVar(fn())
+}
+
+// writeMutable writes response of frequently changing resource which must not be cached by clients and proxies
+func (c *Context) writeMutable(v interface{}, ee ...error) {
+	c.rw.Header().Set("Cache-Control", "no-store")
+	c.WriteVar(v, ee...)
+}
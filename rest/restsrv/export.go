@@ -0,0 +1,217 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+)
+
+var errUnknownExportFormat = errors.New("400 - Unknown export format (expected csv or ofx)")
+
+// formatCoins returns amount in coins as decimal string
+func formatCoins(v bignum.Int) string {
+	return formatAmount(v, coinDecimals)
+}
+
+// formatAmount returns amount in base units as decimal string with decimals places
+func formatAmount(v bignum.Int, decimals int) string {
+	n, unit := v.BigInt(), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	sign := ""
+	if n.Sign() < 0 {
+		sign, n = "-", new(big.Int).Neg(n)
+	}
+	q, r := new(big.Int).QuoRem(n, unit, new(big.Int))
+	if decimals == 0 {
+		return sign + q.String()
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%0*s", decimals, r.String()), "0")
+	if frac == "" {
+		return sign + q.String()
+	}
+	return sign + q.String() + "." + frac
+}
+
+// exportRecord is transfer of address as debit/credit record
+type exportRecord struct {
+	Hash         string
+	Time         time.Time
+	Amount       bignum.Int // positive - credit, negative - debit
+	Counterparty string
+	Memo         string
+}
+
+// exportRecords returns transfers of address from offset (max maxScanTxs)
+// and offset of the next part of history (0 if history is exported to the end)
+func (c *Context) exportRecords(asset, addr []byte, memo uint64, offset uint64) (records []*exportRecord, next uint64) {
+	next = c.scanAddressTxsFrom(asset, addr, memo, offset, false, maxScanTxs, func(tx *chain.Transaction) bool {
+		records = append(records, newExportRecord(c.bc.Cfg, tx, asset, addr, memo))
+		return true
+	})
+	return
+}
+
+// newExportRecord returns record of transaction of address; debit of sender includes fee
+func newExportRecord(cfg *chain.Config, tx *chain.Transaction, asset, addr []byte, memo uint64) *exportRecord {
+	in, out := txBalanceChange(cfg, tx, asset, addr, memo)
+	r := &exportRecord{
+		Hash:   hex.EncodeToString(tx.Hash()),
+		Time:   blockTime(tx.BlockTs),
+		Amount: in.Sub(out),
+	}
+	if tr, ok := tx.TxObject().(*txobj.SimpleTransfer); ok {
+		r.Memo = tr.Comment
+		if out.IsZero() && tx.Sender != nil {
+			r.Counterparty = tx.Sender.StrAddress()
+		} else {
+			for _, o := range tr.Outs {
+				if !bytes.Equal(o.To, addr) {
+					r.Counterparty = crypto.EncodeAddress(o.To, o.ToMemo)
+					break
+				}
+			}
+		}
+	}
+	return r
+}
+
+// exportTxs handles /txs/export?address=<address>&format=csv|ofx&asset=<asset>&offset=<num>.
+// Export is limited by maxScanTxs transactions: truncated export has headers X-Truncated and X-Next-Offset
+// (offset of the next part of history).
+func (c *Context) exportTxs() {
+	addr, memo := c.getAddress("")
+	strAddr := crypto.EncodeAddress(addr, memo)
+	asset := c.getAsset("asset")
+	symbol, decimals := c.assetInfo(asset)
+	format := c.getStr("format", "csv")
+	if format != "csv" && format != "ofx" {
+		c.assert(errUnknownExportFormat)
+	}
+	records, next := c.exportRecords(asset, addr, memo, c.getOffset())
+	var data []byte
+	var contentType string
+	switch format {
+	case "csv":
+		data, contentType = exportCSV(records, decimals), "text/csv; charset=utf-8"
+	case "ofx":
+		info, err := c.bc.AddressInfo(addr, memo, asset)
+		c.assert(err)
+		data, contentType = exportOFX(strAddr, symbol, decimals, info.Balance, records), "application/x-ofx"
+	}
+	h := c.rw.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, strAddr, format))
+	if next != 0 {
+		h.Set("X-Truncated", "true")
+		h.Set("X-Next-Offset", strconv.FormatUint(next, 10))
+	}
+	c.writeHeader(http.StatusOK)
+	c.rw.Write(data)
+}
+
+func exportCSV(records []*exportRecord, decimals int) []byte {
+	buf := bytes.NewBuffer(nil)
+	w := csv.NewWriter(buf)
+	w.Write([]string{"time", "tx_hash", "amount", "counterparty", "comment"})
+	for _, r := range records {
+		w.Write([]string{r.Time.Format(time.RFC3339), r.Hash, formatAmount(r.Amount, decimals), r.Counterparty, r.Memo})
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+// ----------------------- OFX 2.x ---------------------------------------
+type ofxTransaction struct {
+	Type     string `xml:"TRNTYPE"`
+	Posted   string `xml:"DTPOSTED"`
+	Amount   string `xml:"TRNAMT"`
+	FITID    string `xml:"FITID"`
+	Name     string `xml:"NAME,omitempty"`
+	Memo     string `xml:"MEMO,omitempty"`
+	Currency string `xml:"CURRENCY>CURSYM"`
+}
+
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	Signon  struct {
+		Status   ofxStatus `xml:"STATUS"`
+		ServerDT string    `xml:"DTSERVER"`
+		Language string    `xml:"LANGUAGE"`
+	} `xml:"SIGNONMSGSRSV1>SONRS"`
+	Statement struct {
+		TrnUID    string           `xml:"TRNUID"`
+		Status    ofxStatus        `xml:"STATUS"`
+		Currency  string           `xml:"STMTRS>CURDEF"`
+		AccountID string           `xml:"STMTRS>BANKACCTFROM>ACCTID"`
+		BankID    string           `xml:"STMTRS>BANKACCTFROM>BANKID"`
+		AcctType  string           `xml:"STMTRS>BANKACCTFROM>ACCTTYPE"`
+		Start     string           `xml:"STMTRS>BANKTRANLIST>DTSTART"`
+		End       string           `xml:"STMTRS>BANKTRANLIST>DTEND"`
+		Txs       []ofxTransaction `xml:"STMTRS>BANKTRANLIST>STMTTRN"`
+		Balance   string           `xml:"STMTRS>LEDGERBAL>BALAMT"`
+		BalanceDT string           `xml:"STMTRS>LEDGERBAL>DTASOF"`
+	} `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405")
+}
+
+func exportOFX(account, currency string, decimals int, balance bignum.Int, records []*exportRecord) []byte {
+	now := time.Now()
+	doc := &ofxDocument{}
+	doc.Signon.Status = ofxStatus{0, "INFO"}
+	doc.Signon.ServerDT = ofxTime(now)
+	doc.Signon.Language = "ENG"
+	st := &doc.Statement
+	st.TrnUID = "0"
+	st.Status = ofxStatus{0, "INFO"}
+	st.Currency = currency
+	st.AccountID = account
+	st.BankID = "MEDIACOIN"
+	st.AcctType = "CHECKING"
+	st.Start, st.End = ofxTime(now), ofxTime(now)
+	if len(records) > 0 {
+		st.Start, st.End = ofxTime(records[0].Time), ofxTime(records[len(records)-1].Time)
+	}
+	for _, r := range records {
+		typ := "CREDIT"
+		if r.Amount.Sign() < 0 {
+			typ = "DEBIT"
+		}
+		st.Txs = append(st.Txs, ofxTransaction{
+			Type:     typ,
+			Posted:   ofxTime(r.Time),
+			Amount:   formatAmount(r.Amount, decimals),
+			FITID:    r.Hash,
+			Name:     r.Counterparty,
+			Memo:     r.Memo,
+			Currency: currency,
+		})
+	}
+	st.Balance, st.BalanceDT = formatAmount(balance, decimals), ofxTime(now)
+
+	buf := bytes.NewBufferString(xml.Header)
+	buf.WriteString(`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	enc.Encode(doc)
+	return buf.Bytes()
+}
@@ -0,0 +1,24 @@
+package restsrv
+
+import (
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExportRecord_SenderPaysFee(t *testing.T) {
+	bc := newTestChain(t)
+	prv := crypto.NewPrivateKeyBySecret("sender")
+	sender, to := prv.PublicKey().Address(), crypto.NewPrivateKeyBySecret("recipient").PublicKey().Address()
+	tx := txobj.NewSimpleTransfer(bc, prv, assets.MDC, bignum.NewInt(assets.Coin), 0, to, 0, "", 0)
+
+	sent := newExportRecord(bc.Cfg, tx, assets.MDC, sender, 0)
+	received := newExportRecord(bc.Cfg, tx, assets.MDC, to, 0)
+
+	assert.Equal(t, bignum.NewInt(-assets.Coin).Sub(txFee(bc.Cfg, tx)).String(), sent.Amount.String())
+	assert.Equal(t, bignum.NewInt(assets.Coin).String(), received.Amount.String())
+}
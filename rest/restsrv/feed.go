@@ -0,0 +1,69 @@
+package restsrv
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain/bcstore"
+)
+
+const blockFeedInterval = 500 * time.Millisecond
+
+// blockFeed watches blockchain tip and notifies subscribers about new committed blocks
+type blockFeed struct {
+	bc   *bcstore.ChainStorage
+	once sync.Once
+	mx   sync.Mutex
+	subs map[chan uint64]struct{}
+}
+
+func newBlockFeed(bc *bcstore.ChainStorage) *blockFeed {
+	return &blockFeed{
+		bc:   bc,
+		subs: map[chan uint64]struct{}{},
+	}
+}
+
+// subscribe returns channel receiving number of the last committed block.
+// Notifications are coalesced: slow subscriber gets only the latest block number.
+func (f *blockFeed) subscribe() chan uint64 {
+	f.once.Do(func() { go f.run() })
+	ch := make(chan uint64, 1)
+	f.mx.Lock()
+	f.subs[ch] = struct{}{}
+	f.mx.Unlock()
+	return ch
+}
+
+func (f *blockFeed) unsubscribe(ch chan uint64) {
+	f.mx.Lock()
+	delete(f.subs, ch)
+	f.mx.Unlock()
+}
+
+func (f *blockFeed) run() {
+	var lastNum uint64
+	if b := f.bc.LastBlock(); b != nil {
+		lastNum = b.Num
+	}
+	for range time.Tick(blockFeedInterval) {
+		b := f.bc.LastBlock()
+		if b == nil || b.Num == lastNum {
+			continue
+		}
+		lastNum = b.Num
+		f.notify(lastNum)
+	}
+}
+
+func (f *blockFeed) notify(num uint64) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	for ch := range f.subs {
+		select {
+		case <-ch: // drop stale notification
+		default:
+		}
+		ch <- num
+	}
+}
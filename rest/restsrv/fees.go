@@ -0,0 +1,41 @@
+package restsrv
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+)
+
+const feeRatesMaxAge = time.Minute // fee parameters change only with node config or chain upgrades
+
+// feeSampleAmounts are amounts (in coins) of reference transfers of /fee-rates
+var feeSampleAmounts = []int64{1, 100, 10000}
+
+type feeSample struct {
+	Amount bignum.Int `json:"amount"`
+	Fee    bignum.Int `json:"fee"`
+	Size   int64      `json:"size"` // size of binary-encoded transaction (bytes)
+}
+
+type feeRates struct {
+	MinFeeRate int64         `json:"min_fee_rate"` // minimal fee per byte accepted by node, caps size of transaction by TxFee/MinFeeRate (0 - no cap)
+	Chain      *chain.Config `json:"chain"`        // blockchain config, fee parameters checked by transaction verification
+	Samples    []*feeSample  `json:"samples"`      // fees of transfers to MDC-address as charged by /new-transfer
+}
+
+// feeRates handles /fee-rates
+func (c *Context) feeRates() *feeRates {
+	res := &feeRates{MinFeeRate: c.cfg.MinFeeRate, Chain: c.bc.Cfg}
+	to := placeholderKey.PublicKey().Address()
+	for _, coins := range feeSampleAmounts {
+		amount := bignum.NewInt(coins * assets.Coin)
+		tx := txobj.NewSimpleTransfer(c.bc, placeholderKey, assets.MDC, amount, 0, to, 0, "", 0)
+		res.Samples = append(res.Samples, &feeSample{Amount: amount, Fee: txFee(c.bc.Cfg, tx), Size: txSize(tx)})
+	}
+	c.rw.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(feeRatesMaxAge.Seconds())))
+	return res
+}
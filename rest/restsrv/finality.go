@@ -0,0 +1,33 @@
+package restsrv
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+)
+
+var errProbabilisticFinality = errors.New("501 - Blockchain has only probabilistic finality: use confirmations depth")
+
+const finalityProbabilistic = "probabilistic"
+
+type blockFinality struct {
+	BlockNum      uint64 `json:"block_num"`
+	Hash          string `json:"hash"`
+	Status        string `json:"status"` // probabilistic (chain has no finality gadget)
+	Confirmations uint64 `json:"confirmations"`
+	Error         string `json:"error"`
+}
+
+// blockFinality handles /block/<num>/finality.
+// MDC blockchain has no finality gadget, so it responds 501 with status "probabilistic" and confirmations depth of block.
+func (c *Context) blockFinality(num uint64) *blockFinality {
+	block := c.getBlock(num)
+	c.httpStatus = http.StatusNotImplemented
+	return &blockFinality{
+		BlockNum:      num,
+		Hash:          hex.EncodeToString(block.Hash()),
+		Status:        finalityProbabilistic,
+		Confirmations: c.lastBlockNum() - num + 1,
+		Error:         errProbabilisticFinality.Error(),
+	}
+}
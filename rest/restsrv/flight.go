@@ -0,0 +1,69 @@
+package restsrv
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// flightGroup coalesces concurrent identical read requests: callers with the same key share one computation
+type flightGroup struct {
+	mx    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: map[string]*flightCall{}}
+}
+
+// do executes fn once for all concurrent callers with the same key and returns the same result (and error) to all of them
+func (g *flightGroup) do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mx.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mx.Unlock()
+		<-call.done
+		return call.val, call.err
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mx.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			if a, ok := r.(abortedRequest); ok {
+				call.err = a.error
+			} else {
+				call.err = fmt.Errorf("500 - Internal error: %v", r)
+			}
+			g.finish(key, call)
+			panic(r)
+		}
+		g.finish(key, call)
+	}()
+	call.val, call.err = fn()
+	return call.val, call.err
+}
+
+func (g *flightGroup) finish(key string, call *flightCall) {
+	g.mx.Lock()
+	delete(g.calls, key)
+	g.mx.Unlock()
+	close(call.done)
+}
+
+// shared executes read fn coalesced with concurrent identical requests (by path and given params)
+func (c *Context) shared(fn func() (interface{}, error), params ...string) (interface{}, error) {
+	q := url.Values{}
+	for _, name := range params {
+		if c.exists(name) {
+			q.Set(name, c.param(name))
+		}
+	}
+	return c.flights.do(c.uriPath+"?"+q.Encode(), fn)
+}
@@ -0,0 +1,60 @@
+package restsrv
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/common/bin"
+	"github.com/mediacoin-pro/core/crypto"
+)
+
+const genesisAllocationCacheTTL = 24 * time.Hour
+
+type genesisAllocation struct {
+	Address string     `json:"address"`
+	Asset   string     `json:"asset"`
+	Amount  bignum.Int `json:"amount"`
+}
+
+// genesisAllocations returns initial distribution of coins by genesis block (aggregated by address with memo and asset)
+func (c *Context) genesisAllocations() []*genesisAllocation {
+	const key = "genesis-allocation"
+	var res []*genesisAllocation
+	if data, ok := c.Cache.Get(key); ok && bin.Decode(data, &res) == nil {
+		return res
+	}
+	res = []*genesisAllocation{}
+	idx := map[string]*genesisAllocation{}
+	for _, tx := range c.getBlock(0).Txs {
+		tr, ok := tx.TxObject().(*txobj.SimpleTransfer)
+		if !ok {
+			continue
+		}
+		for _, out := range tr.Outs {
+			k := fmt.Sprintf("%x:%x:%d", out.Asset, out.To, out.ToMemo)
+			a := idx[k]
+			if a == nil {
+				a = &genesisAllocation{
+					Address: crypto.EncodeAddress(out.To, out.ToMemo),
+					Asset:   hex.EncodeToString(out.Asset),
+					Amount:  bignum.NewInt(0),
+				}
+				idx[k] = a
+				res = append(res, a)
+			}
+			a.Amount = a.Amount.Add(out.Amount)
+		}
+	}
+	c.Cache.Set(key, bin.Encode(res), genesisAllocationCacheTTL)
+	return res
+}
+
+// genesisAllocation handles /genesis/allocation?offset=<num>&limit=<num>
+func (c *Context) genesisAllocation() *Response {
+	items := c.genesisAllocations()
+	from, to, next := c.pageBounds(len(items))
+	return pageResponse(items[from:to], next)
+}
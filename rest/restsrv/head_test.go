@@ -0,0 +1,52 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_WriteVar_HEAD(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("HEAD", "/info", nil))
+
+	c.WriteVar(&Response{Results: "result"})
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, contentTypeJSON, rw.Header().Get("Content-Type"))
+	assert.Equal(t, 0, rw.Body.Len())
+}
+
+func TestContext_WriteVar_HEADNotFound(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("HEAD", "/tx/00", nil))
+
+	c.WriteVar(nil, errors.New("404 - Not found"))
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestContext_WriteVar_HEADPagination(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("HEAD", "/txs", nil))
+	total := uint64(25)
+
+	c.WriteVar(&Response{Results: []int{1}, NextOffset: "10", NextCursor: "abc", Total: &total})
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "10", rw.Header().Get("X-Next-Offset"))
+	assert.Equal(t, "abc", rw.Header().Get("X-Next-Cursor"))
+	assert.Equal(t, "25", rw.Header().Get("X-Total-Count"))
+	assert.Equal(t, 0, rw.Body.Len())
+}
+
+func TestContext_writeTx_HEADUnknown(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("HEAD", "/tx/00", nil))
+
+	func() {
+		defer func() { recover() }()
+		c.writeTx(nil, nil) // TransactionByHash of unknown transaction
+	}()
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
@@ -0,0 +1,100 @@
+package restsrv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+)
+
+const (
+	maxVerifyHeaders     = 2000
+	maxHeaderFutureDrift = 2 * time.Minute // max time of header ahead of clock of node
+)
+
+var errTooManyHeaders = fmt.Errorf("400 - Too many headers (max %d)", maxVerifyHeaders)
+
+type headerCheck struct {
+	Num       uint64 `json:"num"`
+	Linked    bool   `json:"linked"`    // header refers to previous header (or stored block)
+	Monotonic bool   `json:"monotonic"` // timestamp is greater than previous one and isn't in the future
+	Consensus bool   `json:"consensus"` // header belongs to chain and is signed by its producer
+	Error     string `json:"error,omitempty"`
+}
+
+type headersVerification struct {
+	Valid       bool           `json:"valid"`
+	FailedIndex int            `json:"failed_index"` // index of the first invalid header (-1 if all are valid)
+	Reason      string         `json:"reason,omitempty"`
+	Headers     []*headerCheck `json:"headers"`
+}
+
+// verifyHeaders handles /headers/verify with binary-encoded list of block headers in request body.
+// Headers are checked by themselves, so headers of blocks which node doesn't have yet are verified as well:
+// the first header is linked to the stored previous block if node has it, every next one to the previous header.
+func (c *Context) verifyHeaders() *headersVerification {
+	var headers []*chain.BlockHeader
+	c.getBinary(&headers)
+	if len(headers) > maxVerifyHeaders {
+		c.assert(errTooManyHeaders)
+	}
+	genesis := c.getBlock(0).BlockHeader // identity of chain (network and chain id)
+	now := time.Now()
+	res := &headersVerification{Valid: true, FailedIndex: -1, Headers: []*headerCheck{}}
+	for i, h := range headers {
+		if h == nil {
+			c.assert(errors.New("400 - Empty header"))
+		}
+		chk := &headerCheck{Num: h.Num, Linked: true, Monotonic: true}
+		var prev *chain.BlockHeader
+		if i > 0 {
+			prev = headers[i-1]
+		} else if h.Num > 0 {
+			b, err := c.bc.GetBlock(h.Num - 1)
+			c.assert(err)
+			if b != nil {
+				prev = b.BlockHeader
+			}
+		}
+		if prev != nil {
+			chk.Linked = h.Num == prev.Num+1 && bytes.Equal(h.PrevHash, prev.Hash())
+			chk.Monotonic = h.Timestamp > prev.Timestamp
+		}
+		if blockTime(h.Timestamp).After(now.Add(maxHeaderFutureDrift)) {
+			chk.Monotonic = false
+		}
+		chk.Error = headerConsensusError(genesis, h)
+		chk.Consensus = chk.Error == ""
+		if res.Valid {
+			switch {
+			case !chk.Linked:
+				res.Reason = "header doesn't refer to previous block"
+			case !chk.Monotonic:
+				res.Reason = "timestamp is not greater than previous block timestamp or is in the future"
+			case !chk.Consensus:
+				res.Reason = "consensus rules violation: " + chk.Error
+			}
+			if res.Reason != "" {
+				res.Valid, res.FailedIndex = false, i
+			}
+		}
+		res.Headers = append(res.Headers, chk)
+	}
+	return res
+}
+
+// headerConsensusError returns violation of consensus rules by header ("" if header is valid):
+// header must be of the same network and chain as genesis block and be signed by its producer
+func headerConsensusError(genesis, h *chain.BlockHeader) string {
+	switch {
+	case h.Network != genesis.Network || h.ChainID != genesis.ChainID:
+		return "header belongs to other network or chain"
+	case h.Num == 0 && !bytes.Equal(h.Hash(), genesis.Hash()):
+		return "header differs from genesis block"
+	case h.Miner == nil || !h.Miner.Verify(h.Hash(), h.Sig):
+		return "invalid signature of block producer"
+	}
+	return ""
+}
@@ -0,0 +1,15 @@
+package restsrv
+
+import (
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderConsensusError(t *testing.T) {
+	genesis := &chain.BlockHeader{Network: 1, ChainID: 1}
+
+	assert.Equal(t, "header belongs to other network or chain", headerConsensusError(genesis, &chain.BlockHeader{Network: 2, ChainID: 1, Num: 5}))
+	assert.Equal(t, "invalid signature of block producer", headerConsensusError(genesis, &chain.BlockHeader{Network: 1, ChainID: 1, Num: 5}))
+}
@@ -0,0 +1,79 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const idempotencyTTL = 24 * time.Hour // lifetime of stored responses of write requests
+
+var errIdempotencyInProgress = errors.New("409 - Request with the same Idempotency-Key is in progress")
+
+// idempotentResponse is response of write request stored by Idempotency-Key.
+// Body is stored uncompressed and is compressed on replay as accepted by client.
+type idempotentResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// replayIdempotent handles header Idempotency-Key of write request.
+// The first request with the key is executed and its response is stored in Server.Idempotency;
+// repeated requests get the stored response (with header Idempotent-Replayed) without putting transaction again.
+// Failed requests (error response or panic) release the key, so they can be repeated.
+// It returns true if request is answered by stored response.
+func (c *Context) replayIdempotent() bool {
+	key := c.req.Header.Get("Idempotency-Key")
+	if key == "" || c.Idempotency == nil || !writeEndpoints[c.uriPath] {
+		return false
+	}
+	key = c.uriPath + "\n" + key // keys of different endpoints don't collide
+	ok, data := c.Idempotency.Reserve(key, idempotencyTTL)
+	if ok {
+		c.idempotencyKey = key
+		return false
+	}
+	if data == nil {
+		c.assert(errIdempotencyInProgress)
+	}
+	var resp idempotentResponse
+	c.assert(json.Unmarshal(data, &resp))
+	h := c.rw.Header()
+	h.Set("Content-Type", resp.ContentType)
+	h.Set("Idempotent-Replayed", "true")
+	c.writeBody(resp.Status, bytes.NewReader(resp.Body))
+	return true
+}
+
+// storeIdempotent stores uncompressed response of write request with Idempotency-Key (httpCode 0 - 200).
+// Error responses aren't stored: the key is released.
+func (c *Context) storeIdempotent(httpCode int, body []byte) {
+	if c.idempotencyKey == "" {
+		return
+	}
+	if httpCode == 0 {
+		httpCode = http.StatusOK
+	}
+	if httpCode >= http.StatusBadRequest {
+		c.releaseIdempotent()
+		return
+	}
+	data, _ := json.Marshal(&idempotentResponse{
+		Status:      httpCode,
+		ContentType: c.rw.Header().Get("Content-Type"),
+		Body:        body,
+	})
+	c.Idempotency.Complete(c.idempotencyKey, data, idempotencyTTL)
+	c.idempotencyKey = ""
+}
+
+// releaseIdempotent releases Idempotency-Key reserved by request which failed without stored response
+func (c *Context) releaseIdempotent() {
+	if c.idempotencyKey != "" {
+		c.Idempotency.Release(c.idempotencyKey)
+		c.idempotencyKey = ""
+	}
+}
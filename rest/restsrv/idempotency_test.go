@@ -0,0 +1,102 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestIdempotentContext(store IdempotencyStore, key string) (*Context, *httptest.ResponseRecorder) {
+	s := &Server{Idempotency: store}
+	s.setConfig(&Config{})
+	req := httptest.NewRequest("POST", "/new-transfer", nil)
+	req.Header.Set("Idempotency-Key", key)
+	rw := httptest.NewRecorder()
+	return newContext(s, req, rw), rw
+}
+
+func TestContext_replayIdempotent(t *testing.T) {
+	store := NewMemIdempotencyStore()
+
+	c1, rw1 := newTestIdempotentContext(store, "k1")
+	replayed1 := c1.replayIdempotent()
+	c1.WriteVar(map[string]string{"hash": "01"})
+
+	c2, rw2 := newTestIdempotentContext(store, "k1")
+	replayed2 := c2.replayIdempotent()
+
+	assert.False(t, replayed1)
+	assert.True(t, replayed2)
+	assert.Equal(t, http.StatusOK, rw2.Code)
+	assert.Equal(t, "true", rw2.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, contentTypeJSON, rw2.Header().Get("Content-Type"))
+	assert.Equal(t, rw1.Body.String(), rw2.Body.String())
+}
+
+func TestContext_replayIdempotent_InProgress(t *testing.T) {
+	store := NewMemIdempotencyStore()
+	c1, _ := newTestIdempotentContext(store, "k1")
+	c1.replayIdempotent()
+
+	c2, rw2 := newTestIdempotentContext(store, "k1")
+	assert.Panics(t, func() { c2.replayIdempotent() })
+
+	assert.Equal(t, http.StatusConflict, rw2.Code)
+}
+
+func TestContext_replayIdempotent_OtherKey(t *testing.T) {
+	store := NewMemIdempotencyStore()
+	c1, _ := newTestIdempotentContext(store, "k1")
+	c1.replayIdempotent()
+	c1.WriteVar(0)
+
+	c2, _ := newTestIdempotentContext(store, "k2")
+
+	assert.False(t, c2.replayIdempotent())
+}
+
+func TestContext_replayIdempotent_Compressed(t *testing.T) {
+	store := NewMemIdempotencyStore()
+	body := strings.Repeat("x", minCompressSize)
+
+	c1, _ := newTestIdempotentContext(store, "k1")
+	c1.req.Header.Set("Accept-Encoding", "gzip")
+	c1.replayIdempotent()
+	c1.WriteVar(body)
+
+	c2, rw2 := newTestIdempotentContext(store, "k1")
+	replayed := c2.replayIdempotent()
+
+	assert.True(t, replayed)
+	assert.Equal(t, "", rw2.Header().Get("Content-Encoding"))
+	assert.Contains(t, rw2.Body.String(), body)
+}
+
+func TestContext_replayIdempotent_ReleasedOnError(t *testing.T) {
+	store := NewMemIdempotencyStore()
+	c1, _ := newTestIdempotentContext(store, "k1")
+	c1.replayIdempotent()
+	assert.Panics(t, func() { c1.assert(errors.New("400 - Insufficient funds")) })
+
+	c2, _ := newTestIdempotentContext(store, "k1")
+
+	assert.False(t, c2.replayIdempotent())
+}
+
+func TestContext_replayIdempotent_ReleasedOnPanic(t *testing.T) {
+	store := NewMemIdempotencyStore()
+	c1, _ := newTestIdempotentContext(store, "k1")
+	c1.replayIdempotent()
+	func() {
+		defer c1.recoverPanic()
+		panic("unexpected")
+	}()
+
+	c2, _ := newTestIdempotentContext(store, "k1")
+
+	assert.False(t, c2.replayIdempotent())
+}
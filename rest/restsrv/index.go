@@ -0,0 +1,119 @@
+package restsrv
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/bcstore"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/xlog"
+)
+
+const chainIndexInterval = 10 * time.Second
+
+// chainIndex indexes blockchain data which storage can't look up:
+// assets with transactions which issued them (the first transfer of asset) and block numbers by block hash.
+// Index is built by scanning committed blocks; it's caught up on every lookup and in background.
+type chainIndex struct {
+	bc     *bcstore.ChainStorage
+	mx     sync.Mutex
+	next   uint64                        // number of the next block to index
+	assets [][]byte                      // assets in order of issue (MDC is the first)
+	issues map[string]*chain.Transaction // asset -> transaction which issued asset
+	blocks map[string]uint64             // block hash -> block num
+}
+
+func newChainIndex(bc *bcstore.ChainStorage) *chainIndex {
+	return &chainIndex{
+		bc:     bc,
+		assets: [][]byte{assets.MDC},
+		issues: map[string]*chain.Transaction{},
+		blocks: map[string]uint64{},
+	}
+}
+
+// run catches up index in background until closing is closed
+func (x *chainIndex) run(closing <-chan struct{}) {
+	t := time.NewTicker(chainIndexInterval)
+	defer t.Stop()
+	for {
+		if err := x.update(); err != nil {
+			xlog.Error.Printf("rest> chain-index: %v", err)
+		}
+		select {
+		case <-t.C:
+		case <-closing:
+			return
+		}
+	}
+}
+
+// update indexes blocks committed since the last update
+func (x *chainIndex) update() error {
+	x.mx.Lock()
+	defer x.mx.Unlock()
+	last := x.bc.LastBlock()
+	for ; last != nil && x.next <= last.Num; x.next++ {
+		block, err := x.bc.GetBlock(x.next)
+		if err != nil {
+			return err
+		}
+		if block != nil {
+			x.add(block)
+		}
+	}
+	return nil
+}
+
+func (x *chainIndex) add(block *chain.Block) {
+	x.blocks[string(block.Hash())] = block.Num
+	for _, tx := range block.Txs {
+		tr, ok := tx.TxObject().(*txobj.SimpleTransfer)
+		if !ok {
+			continue
+		}
+		for _, out := range tr.Outs {
+			if _, ok := x.issues[string(out.Asset)]; ok {
+				continue
+			}
+			x.issues[string(out.Asset)] = tx
+			if !bytes.Equal(out.Asset, assets.MDC) {
+				x.assets = append(x.assets, out.Asset)
+			}
+		}
+	}
+}
+
+// assetList returns ids of assets issued in blockchain
+func (x *chainIndex) assetList() ([][]byte, error) {
+	if err := x.update(); err != nil {
+		return nil, err
+	}
+	x.mx.Lock()
+	defer x.mx.Unlock()
+	return append([][]byte{}, x.assets...), nil
+}
+
+// assetIssue returns transaction which issued asset (nil if asset is unknown)
+func (x *chainIndex) assetIssue(asset []byte) (*chain.Transaction, error) {
+	if err := x.update(); err != nil {
+		return nil, err
+	}
+	x.mx.Lock()
+	defer x.mx.Unlock()
+	return x.issues[string(asset)], nil
+}
+
+// blockNum returns number of block by block hash
+func (x *chainIndex) blockNum(hash []byte) (num uint64, ok bool, err error) {
+	if err = x.update(); err != nil {
+		return
+	}
+	x.mx.Lock()
+	defer x.mx.Unlock()
+	num, ok = x.blocks[string(hash)]
+	return
+}
@@ -0,0 +1,43 @@
+package restsrv
+
+import (
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainIndex_assetIssue(t *testing.T) {
+	bc := newTestChain(t)
+	x := newChainIndex(bc)
+	prv, to := crypto.NewPrivateKeyBySecret("issuer"), crypto.NewPrivateKeyBySecret("recipient").PublicKey().Address()
+	usd := []byte("USD")
+	tx1 := txobj.NewSimpleTransfer(bc, prv, usd, bignum.NewInt(100), 0, to, 0, "", 1)
+	tx2 := txobj.NewSimpleTransfer(bc, prv, usd, bignum.NewInt(200), 0, to, 0, "", 2)
+	x.add(&chain.Block{BlockHeader: &chain.BlockHeader{Num: 1}, Txs: []*chain.Transaction{tx1, tx2}})
+
+	issue, err := x.assetIssue(usd)
+	assert.NoError(t, err)
+	assert.Equal(t, tx1, issue)
+
+	issue, err = x.assetIssue([]byte("EUR"))
+	assert.NoError(t, err)
+	assert.Nil(t, issue)
+	assert.Equal(t, [][]byte{assets.MDC, usd}, x.assets)
+}
+
+func TestChainIndex_blockNum(t *testing.T) {
+	x := newChainIndex(newTestChain(t))
+	block := &chain.Block{BlockHeader: &chain.BlockHeader{Num: 7, Timestamp: 1500000000}}
+	x.add(block)
+
+	num, ok, err := x.blockNum(block.Hash())
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), num)
+}
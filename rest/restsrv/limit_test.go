@@ -0,0 +1,32 @@
+package restsrv
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_getLimit(t *testing.T) {
+	for url, exp := range map[string]int64{
+		"/blocks":            defaultLimit,
+		"/blocks?limit=50":   50,
+		"/blocks?limit=1000": maxLimit,
+	} {
+		c, rw := newTestContext(httptest.NewRequest("GET", url, nil))
+
+		assert.Equal(t, exp, c.getLimit(), url)
+		assert.Equal(t, url == "/blocks?limit=1000", rw.Header().Get("X-Limit-Clamped") == "true", url)
+	}
+}
+
+func TestContext_getLimit_configured(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("GET", "/blocks?limit=1000", nil))
+	c.DefaultLimit, c.MaxLimit = 500, 5000
+
+	assert.EqualValues(t, 1000, c.getLimit())
+	assert.Equal(t, "", rw.Header().Get("X-Limit-Clamped"))
+
+	c.reqQuery.Del("limit")
+	assert.EqualValues(t, 500, c.getLimit())
+}
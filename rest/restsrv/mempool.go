@@ -0,0 +1,379 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/common/bignum"
+)
+
+var errMempoolNotEnumerable = errors.New("501 - Mempool of node can't be enumerated: transactions submitted through this node are listed by /submitted")
+
+type mempoolTxDeps struct {
+	Hash     string     `json:"hash"`
+	Sender   string     `json:"sender"`
+	Nonce    uint64     `json:"nonce"`
+	Fee      bignum.Int `json:"fee"`
+	Size     int64      `json:"size"`
+	Parents  []string   `json:"parents"`  // pending transactions which must be confirmed before this one
+	Children []string   `json:"children"` // pending transactions which depend on this one
+}
+
+// mempoolDependencies returns pending transactions with their dependencies.
+// Transactions of the same sender are chained by nonce: every transaction depends on the previous pending one.
+func mempoolDependencies(cfg *chain.Config, txs []*chain.Transaction) []*mempoolTxDeps {
+	bySender := map[string][]*chain.Transaction{}
+	var senders []string
+	for _, tx := range txs {
+		var sender string
+		if tx.Sender != nil {
+			sender = tx.Sender.StrAddress()
+		}
+		if _, ok := bySender[sender]; !ok {
+			senders = append(senders, sender)
+		}
+		bySender[sender] = append(bySender[sender], tx)
+	}
+	res := make([]*mempoolTxDeps, 0, len(txs))
+	for _, sender := range senders {
+		chainTxs := bySender[sender]
+		sort.SliceStable(chainTxs, func(i, j int) bool { return chainTxs[i].Nonce < chainTxs[j].Nonce })
+		var prev *mempoolTxDeps
+		for _, tx := range chainTxs {
+			d := &mempoolTxDeps{
+				Hash:     hex.EncodeToString(tx.Hash()),
+				Sender:   sender,
+				Nonce:    tx.Nonce,
+				Fee:      txFee(cfg, tx),
+				Size:     txSize(tx),
+				Parents:  []string{},
+				Children: []string{},
+			}
+			if prev != nil && sender != "" {
+				d.Parents = append(d.Parents, prev.Hash)
+				prev.Children = append(prev.Children, d.Hash)
+			}
+			res = append(res, d)
+			prev = d
+		}
+	}
+	return res
+}
+
+// putTx puts transaction to mempool
+func (c *Context) putTx(tx *chain.Transaction) error {
+	err := errTxDuplicate
+	if !c.isConfirmed(tx.Hash()) {
+		err = c.bc.Mempool.Put(tx)
+		if err != nil {
+			err = txRejectedError(err)
+		}
+	}
+	if err != nil {
+		c.recordRejection(err)
+		return err
+	}
+	c.metrics.observePutTx(true)
+	c.submitted.put(c.Server, tx, time.Now())
+	return nil
+}
+
+const (
+	submittedSweepInterval = time.Minute
+	submittedTxTTL         = 24 * time.Hour // unconfirmed transactions older than this are considered dropped by mempool
+)
+
+// submittedTx is transaction put to mempool by REST service
+type submittedTx struct {
+	tx   *chain.Transaction
+	seen time.Time // time when transaction was put to mempool
+}
+
+// submittedTxs keeps transactions put to mempool by REST service until they are confirmed (or expire).
+// Mempool of node doesn't enumerate pending transactions (endpoints /mempool... respond 501),
+// so endpoints /submitted... list unconfirmed transactions submitted through this node.
+type submittedTxs struct {
+	once sync.Once
+	mx   sync.Mutex
+	m    map[string]*submittedTx
+}
+
+func newSubmittedTxs() *submittedTxs {
+	return &submittedTxs{m: map[string]*submittedTx{}}
+}
+
+// put remembers transaction put to mempool; confirmed and expired transactions are forgotten by background sweep
+func (s *submittedTxs) put(srv *Server, tx *chain.Transaction, t time.Time) {
+	s.once.Do(func() { go s.run(srv) })
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if key := string(tx.Hash()); s.m[key] == nil {
+		s.m[key] = &submittedTx{tx, t}
+	}
+}
+
+func (s *submittedTxs) run(srv *Server) {
+	t := time.NewTicker(submittedSweepInterval)
+	defer t.Stop()
+	for {
+		select {
+		case now := <-t.C:
+			s.pending(srv.isConfirmed, now)
+		case <-srv.closing:
+			return
+		}
+	}
+}
+
+// pending returns unconfirmed transactions in order of submission and forgets confirmed and expired ones
+func (s *submittedTxs) pending(isConfirmed func(txHash []byte) bool, now time.Time) []*submittedTx {
+	s.mx.Lock()
+	list := make([]*submittedTx, 0, len(s.m))
+	for key, st := range s.m {
+		if st.seen.Before(now.Add(-submittedTxTTL)) {
+			delete(s.m, key)
+		} else {
+			list = append(list, st)
+		}
+	}
+	s.mx.Unlock()
+
+	res := list[:0]
+	for _, st := range list { // storage isn't queried under lock
+		if isConfirmed(st.tx.Hash()) {
+			s.forget(st.tx)
+		} else {
+			res = append(res, st)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].seen.Before(res[j].seen) })
+	return res
+}
+
+func (s *submittedTxs) forget(tx *chain.Transaction) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	delete(s.m, string(tx.Hash()))
+}
+
+// isConfirmed returns true if transaction is stored in blockchain
+func (s *Server) isConfirmed(txHash []byte) bool {
+	tx, err := s.bc.TransactionByHash(txHash)
+	return err == nil && tx != nil
+}
+
+// medianFeeRate returns median fee rate of pending transactions
+func medianFeeRate(cfg *chain.Config, txs []*chain.Transaction) float64 {
+	if len(txs) == 0 {
+		return 0
+	}
+	rates := make([]float64, len(txs))
+	for i, tx := range txs {
+		rates[i] = txFeeRate(cfg, tx)
+	}
+	sort.Float64s(rates)
+	return rates[len(rates)/2]
+}
+
+type stuckTx struct {
+	Hash         string     `json:"hash"`
+	Nonce        uint64     `json:"nonce"`
+	AgeSeconds   int64      `json:"age_seconds"`
+	Fee          bignum.Int `json:"fee"`
+	FeeRate      float64    `json:"fee_rate"`
+	ThresholdFee float64    `json:"threshold_fee_rate"` // median fee rate of mempool (or min fee rate of node if greater)
+}
+
+// stuckTxs handles /address/<address>/stuck?min_age_seconds=<sec>.
+// Age of transaction is counted from the moment when it was put to mempool through REST service.
+// Fee of transaction is fixed (cfg.TxFee) and can't be raised, so fee rate below threshold only means that transaction is larger than usual.
+func (c *Context) stuckTxs(addr []byte) []*stuckTx {
+	minAge := time.Duration(c.getUint("min_age_seconds")) * time.Second
+	if minAge == 0 {
+		minAge = 10 * time.Minute
+	}
+	pending := c.submitted.pending(c.isConfirmed, time.Now())
+	txs := make([]*chain.Transaction, len(pending))
+	for i, st := range pending {
+		txs[i] = st.tx
+	}
+	threshold := medianFeeRate(c.bc.Cfg, txs)
+	if minRate := float64(c.cfg.MinFeeRate); threshold < minRate {
+		threshold = minRate
+	}
+	now := time.Now()
+	res := []*stuckTx{}
+	for _, st := range pending {
+		tx := st.tx
+		if tx.Sender == nil || !bytes.Equal(tx.Sender.Address(), addr) || now.Sub(st.seen) < minAge {
+			continue
+		}
+		st := &stuckTx{
+			Hash:         hex.EncodeToString(tx.Hash()),
+			Nonce:        tx.Nonce,
+			AgeSeconds:   int64(now.Sub(st.seen).Seconds()),
+			Fee:          txFee(c.bc.Cfg, tx),
+			FeeRate:      txFeeRate(c.bc.Cfg, tx),
+			ThresholdFee: threshold,
+		}
+		res = append(res, st)
+	}
+	return res
+}
+
+type admissionResult struct {
+	WouldAccept bool   `json:"would_accept"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+var errTxAlreadyExists = errors.New("Transaction already exists")
+
+// mempoolCheck returns whether mempool would accept transaction now. It has no side effects on mempool.
+// Transaction is checked as simulateTxs does (signature, fee rate, nonce and balances of sender)
+// over actual state with pending transactions of sender submitted through this node.
+func (c *Context) mempoolCheck(tx *chain.Transaction) *admissionResult {
+	err := tx.Verify(c.bc.Cfg)
+	if err == nil {
+		err = c.checkFeeRate(tx)
+	}
+	if err == nil {
+		err = c.checkAdmission(newBalanceOverlay(c), tx)
+	}
+	if err != nil {
+		return &admissionResult{false, err.Error()}
+	}
+	return &admissionResult{WouldAccept: true}
+}
+
+// checkAdmission applies pending transactions of sender and then transaction to overlay of actual state:
+// nonce of transaction must follow the last pending or committed transaction of sender (stale and duplicate nonces are rejected)
+func (c *Context) checkAdmission(overlay *balanceOverlay, tx *chain.Transaction) error {
+	if c.isConfirmed(tx.Hash()) {
+		return errTxAlreadyExists
+	}
+	for _, pending := range c.submittedPending() {
+		if bytes.Equal(pending.Hash(), tx.Hash()) {
+			return errTxAlreadyExists
+		}
+		if tx.Sender != nil && pending.Sender != nil && bytes.Equal(pending.Sender.Address(), tx.Sender.Address()) {
+			overlay.apply(pending)
+		}
+	}
+	return overlay.apply(tx)
+}
+
+type sortedMempoolTx struct {
+	Position int                `json:"position"` // position in sorted list (from 0), not in mining queue
+	Hash     string             `json:"hash"`
+	Fee      bignum.Int         `json:"fee"`
+	Size     int64              `json:"size"`
+	Tx       *chain.Transaction `json:"tx"`
+}
+
+// sortedSubmitted handles /submitted/sorted?order=fee_desc|fee_asc&offset=<num>&limit=<num>.
+// Every transaction of sender pays fixed fee cfg.TxFee, so fee rate (fee per byte) depends only on size of transaction:
+// order=fee_desc lists smaller transactions first.
+func (c *Context) sortedSubmitted() *Response {
+	txs := c.submittedPending()
+	sort.SliceStable(txs, func(i, j int) bool { return txFeeRate(c.bc.Cfg, txs[i]) > txFeeRate(c.bc.Cfg, txs[j]) })
+	items := make([]*sortedMempoolTx, len(txs))
+	for i, tx := range txs {
+		items[i] = &sortedMempoolTx{Position: i, Hash: hex.EncodeToString(tx.Hash()), Fee: txFee(c.bc.Cfg, tx), Size: txSize(tx), Tx: tx}
+	}
+	switch order := c.getStr("order", "fee_desc"); order {
+	case "fee_desc":
+	case "fee_asc":
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	default:
+		c.assert(errors.New("400 - Unknown order (expected fee_desc or fee_asc)"))
+	}
+	from, to, next := c.pageBounds(len(items))
+	return pageResponse(items[from:to], next)
+}
+
+const maxPackageSize = 100 // max count of transactions in mempool package
+
+type mempoolPackage struct {
+	Tx          *mempoolTxDeps   `json:"tx"`
+	Ancestors   []*mempoolTxDeps `json:"ancestors"`   // unconfirmed transactions which must be confirmed before tx
+	Descendants []*mempoolTxDeps `json:"descendants"` // unconfirmed transactions which depend on tx
+	Size        int64            `json:"size"`
+	Fee         bignum.Int       `json:"fee"`
+	Truncated   bool             `json:"truncated,omitempty"` // package is larger than maxPackageSize
+}
+
+// submittedPackage handles /submitted/<hash>/package
+func (c *Context) submittedPackage(txHash []byte) *mempoolPackage {
+	deps := map[string]*mempoolTxDeps{}
+	for _, d := range mempoolDependencies(c.bc.Cfg, c.submittedPending()) {
+		deps[d.Hash] = d
+	}
+	tx := deps[hex.EncodeToString(txHash)]
+	c.assertFound(tx != nil)
+
+	res := &mempoolPackage{
+		Tx:          tx,
+		Ancestors:   []*mempoolTxDeps{},
+		Descendants: []*mempoolTxDeps{},
+		Size:        tx.Size,
+		Fee:         tx.Fee,
+	}
+	seen := map[string]bool{tx.Hash: true}
+	walk := func(links func(d *mempoolTxDeps) []string, list *[]*mempoolTxDeps) {
+		queue := links(tx)
+		for len(queue) > 0 {
+			h := queue[0]
+			queue = queue[1:]
+			d := deps[h]
+			if d == nil || seen[h] {
+				continue
+			}
+			if len(seen) >= maxPackageSize {
+				res.Truncated = true
+				return
+			}
+			seen[h] = true
+			*list = append(*list, d)
+			res.Size, res.Fee = res.Size+d.Size, res.Fee.Add(d.Fee)
+			queue = append(queue, links(d)...)
+		}
+	}
+	walk(func(d *mempoolTxDeps) []string { return d.Parents }, &res.Ancestors)
+	walk(func(d *mempoolTxDeps) []string { return d.Children }, &res.Descendants)
+	return res
+}
+
+// submittedTxsPage handles /submitted?offset=<num>&limit=<num>&order=asc|desc.
+// Transactions are listed in order of submission (reversed for order=desc).
+func (c *Context) submittedTxsPage() *Response {
+	txs := c.submittedPending()
+	if c.getOrderDesc() {
+		for i, j := 0, len(txs)-1; i < j; i, j = i+1, j-1 {
+			txs[i], txs[j] = txs[j], txs[i]
+		}
+	}
+	from, to, next := c.pageBounds(len(txs))
+	return pageResponse(txs[from:to], next)
+}
+
+type mempoolSize struct {
+	Count int   `json:"count"` // count of pending transactions
+	Bytes int64 `json:"bytes"` // total size of encoded pending transactions
+}
+
+// submittedSize handles /submitted/size
+func (c *Context) submittedSize() *mempoolSize {
+	txs := c.submittedPending()
+	res := &mempoolSize{Count: len(txs)}
+	for _, tx := range txs {
+		res.Bytes += txSize(tx)
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package restsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmittedTxs_pending(t *testing.T) {
+	now := time.Now()
+	txOld, txConfirmed, tx1, tx2 := &chain.Transaction{Nonce: 1}, &chain.Transaction{Nonce: 2}, &chain.Transaction{Nonce: 3}, &chain.Transaction{Nonce: 4}
+	s := newSubmittedTxs()
+	s.m[string(txOld.Hash())] = &submittedTx{txOld, now.Add(-25 * time.Hour)}
+	s.m[string(txConfirmed.Hash())] = &submittedTx{txConfirmed, now.Add(-time.Hour)}
+	s.m[string(tx2.Hash())] = &submittedTx{tx2, now.Add(-time.Minute)}
+	s.m[string(tx1.Hash())] = &submittedTx{tx1, now.Add(-time.Hour)}
+	isConfirmed := func(txHash []byte) bool { return string(txHash) == string(txConfirmed.Hash()) }
+
+	pending := s.pending(isConfirmed, now)
+
+	assert.Len(t, pending, 2)
+	assert.Equal(t, tx1, pending[0].tx) // in order of submission
+	assert.Equal(t, tx2, pending[1].tx)
+	assert.Len(t, s.m, 2)
+}
+
+func TestContext_mempoolCheck(t *testing.T) {
+	s := &Server{bc: newTestChain(t), submitted: newSubmittedTxs()}
+	s.setConfig(&Config{})
+	c := newContext(s, httptest.NewRequest("POST", "/mempool/check", nil), httptest.NewRecorder())
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().Address()
+	tx := txobj.NewSimpleTransfer(s.bc, crypto.NewPrivateKeyBySecret("sender"), assets.MDC, bignum.NewInt(assets.Coin), 0, to, 0, "", 0)
+
+	res := c.mempoolCheck(tx)
+
+	assert.False(t, res.WouldAccept)
+	assert.Equal(t, errInsufficientFunds.Error(), res.Reason) // sender of empty chain has no funds
+
+	s.submitted.m[string(tx.Hash())] = &submittedTx{tx, time.Now()}
+	res = c.mempoolCheck(tx)
+
+	assert.False(t, res.WouldAccept)
+	assert.Equal(t, errTxAlreadyExists.Error(), res.Reason)
+}
+
+func TestContext_Exec_MempoolNotEnumerable(t *testing.T) {
+	for _, path := range []string{"/mempool", "/mempool/size", "/mempool/sorted", "/mempool/dependencies"} {
+		c, rw := newTestContext(httptest.NewRequest("GET", path, nil))
+
+		assert.Panics(t, c.Exec, path)
+
+		assert.Equal(t, http.StatusNotImplemented, rw.Code, path)
+	}
+}
+
+func TestContext_submittedSize(t *testing.T) {
+	s := &Server{bc: newTestChain(t), submitted: newSubmittedTxs()}
+	tx := &chain.Transaction{Nonce: 1}
+	s.submitted.m[string(tx.Hash())] = &submittedTx{tx, time.Now()}
+	c := &Context{Server: s}
+
+	assert.Equal(t, 1, c.submittedSize().Count)
+}
+
+func TestContext_checkAdmission_StaleNonce(t *testing.T) {
+	s := &Server{bc: newTestChain(t), submitted: newSubmittedTxs()}
+	c := &Context{Server: s}
+	sender := crypto.NewPrivateKeyBySecret("sender")
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().Address()
+	o := newTestBalanceOverlay(map[string]int64{string(sender.PublicKey().Address()): 10 * assets.Coin})
+	o.c = c
+	o.chainNonce = func([]byte) (uint64, bool) { return 7, true } // nonce of the last committed transaction of sender
+
+	stale := txobj.NewSimpleTransfer(s.bc, sender, assets.MDC, bignum.NewInt(1), 0, to, 0, "", 7)
+	next := txobj.NewSimpleTransfer(s.bc, sender, assets.MDC, bignum.NewInt(1), 0, to, 0, "", 8)
+
+	assert.EqualError(t, c.checkAdmission(o, stale), "Nonce 7 doesn't follow nonce 7 of previous transaction of sender")
+	assert.NoError(t, c.checkAdmission(o, next))
+}
@@ -0,0 +1,16 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+)
+
+// errNoTxRootAlgorithm is response of /merkle-root: blockchain core doesn't expose construction of transactions root of block,
+// and root computed by other construction would not match blocks.
+var errNoTxRootAlgorithm = errors.New("501 - Merkle root can't be computed: blockchain core doesn't expose construction of transactions root")
+
+// merkleRoot handles /merkle-root
+func (c *Context) merkleRoot() interface{} {
+	c.abort(errNoTxRootAlgorithm, http.StatusNotImplemented)
+	return nil
+}
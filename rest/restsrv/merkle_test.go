@@ -0,0 +1,17 @@
+package restsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_merkleRoot(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("GET", "/merkle-root?hashes=00", nil))
+
+	assert.Panics(t, func() { c.merkleRoot() })
+
+	assert.Equal(t, http.StatusNotImplemented, rw.Code)
+}
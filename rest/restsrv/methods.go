@@ -0,0 +1,51 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var errMethodNotAllowed = errors.New("405 - Method not allowed")
+
+const (
+	readMethods  = "GET, HEAD"
+	bodyMethods  = "GET, HEAD, POST" // read endpoints taking data or secrets in request body
+	writeMethods = "POST, PUT"
+)
+
+// endpointMethods are allowed methods of endpoints (readMethods for other endpoints, writeMethods for writeEndpoints)
+var endpointMethods = map[string]string{
+	"/headers/verify": "POST",
+	"/admin/reload":   "POST",
+	"/merkle-root":    bodyMethods,
+	"/mempool/check":  bodyMethods,
+	"/txs/simulate":   bodyMethods,
+	"/txs/batch":      bodyMethods,
+	"/txs/filtered":   bodyMethods,
+	"/estimate-fee":   bodyMethods,
+	"/new-key":        bodyMethods,
+}
+
+// allowedMethods returns allowed http-methods of request path
+func (c *Context) allowedMethods() string {
+	if writeEndpoints[c.uriPath] {
+		return writeMethods
+	}
+	if m, ok := endpointMethods[c.uriPath]; ok {
+		return m
+	}
+	return readMethods
+}
+
+// assertMethod aborts request with 405-response (and header Allow) if http-method isn't allowed for endpoint
+func (c *Context) assertMethod() {
+	allowed := c.allowedMethods()
+	for _, m := range strings.Split(allowed, ", ") {
+		if c.req.Method == m {
+			return
+		}
+	}
+	c.rw.Header().Set("Allow", allowed)
+	c.abort(errMethodNotAllowed, http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,205 @@
+package restsrv
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const contentTypeMetrics = "text/plain; version=0.0.4; charset=utf-8"
+
+// bounds of buckets of request latency histogram (in seconds)
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metrics is registry of REST service metrics exposed in Prometheus text format by /metrics
+type metrics struct {
+	mx       sync.Mutex
+	requests map[requestKey]uint64 // count of requests by route and http-status
+	latency  map[string]*histogram // latency of requests by route
+	putTxs   map[string]uint64     // count of transaction submissions by result ("success", "failure")
+}
+
+type requestKey struct {
+	route  string
+	status int
+}
+
+type histogram struct {
+	counts []uint64 // counts by bucket (not cumulative)
+	sum    float64
+	count  uint64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		requests: map[requestKey]uint64{},
+		latency:  map[string]*histogram{},
+		putTxs:   map[string]uint64{},
+	}
+}
+
+func (m *metrics) observeRequest(route string, status int, d time.Duration) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.requests[requestKey{route, status}]++
+	h := m.latency[route]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(latencyBuckets))}
+		m.latency[route] = h
+	}
+	sec := d.Seconds()
+	if i := sort.SearchFloat64s(latencyBuckets, sec); i < len(latencyBuckets) {
+		h.counts[i]++
+	}
+	h.sum += sec
+	h.count++
+}
+
+func (m *metrics) observePutTx(ok bool) {
+	result := "success"
+	if !ok {
+		result = "failure"
+	}
+	m.mx.Lock()
+	m.putTxs[result]++
+	m.mx.Unlock()
+}
+
+// requestsCount returns count of requests of route with http-status
+func (m *metrics) requestsCount(route string, status int) uint64 {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	return m.requests[requestKey{route, status}]
+}
+
+func (m *metrics) write(w *bufio.Writer) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	keys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].route < keys[j].route || keys[i].route == keys[j].route && keys[i].status < keys[j].status
+	})
+	writeMetricHeader(w, "mdc_rest_requests_total", "counter", "Total REST API requests by route and http-status.")
+	for _, k := range keys {
+		fmt.Fprintf(w, "mdc_rest_requests_total{route=%q,status=\"%d\"} %d\n", k.route, k.status, m.requests[k])
+	}
+
+	routes := make([]string, 0, len(m.latency))
+	for route := range m.latency {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	writeMetricHeader(w, "mdc_rest_request_duration_seconds", "histogram", "Latency of REST API requests by route.")
+	for _, route := range routes {
+		h := m.latency[route]
+		var n uint64
+		for i, le := range latencyBuckets {
+			n += h.counts[i]
+			fmt.Fprintf(w, "mdc_rest_request_duration_seconds_bucket{route=%q,le=%q} %d\n", route, strconv.FormatFloat(le, 'g', -1, 64), n)
+		}
+		fmt.Fprintf(w, "mdc_rest_request_duration_seconds_bucket{route=%q,le=\"+Inf\"} %d\n", route, h.count)
+		fmt.Fprintf(w, "mdc_rest_request_duration_seconds_sum{route=%q} %g\n", route, h.sum)
+		fmt.Fprintf(w, "mdc_rest_request_duration_seconds_count{route=%q} %d\n", route, h.count)
+	}
+
+	writeMetricHeader(w, "mdc_rest_put_tx_total", "counter", "Transaction submissions by result.")
+	for _, result := range []string{"success", "failure"} {
+		fmt.Fprintf(w, "mdc_rest_put_tx_total{result=%q} %d\n", result, m.putTxs[result])
+	}
+}
+
+func writeMetricHeader(w *bufio.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func writeGauge(w *bufio.Writer, name, help string, v interface{}) {
+	writeMetricHeader(w, name, "gauge", help)
+	fmt.Fprintf(w, "%s %v\n", name, v)
+}
+
+// serveMetrics handles /metrics
+func (c *Context) serveMetrics() {
+	c.rw.Header().Set("Content-Type", contentTypeMetrics)
+	c.rw.Header().Set("Cache-Control", "no-store")
+	c.writeHeader(http.StatusOK)
+	w := bufio.NewWriter(c.rw)
+	defer w.Flush()
+
+	c.metrics.write(w)
+	if last := c.bc.LastBlock(); last != nil {
+		writeGauge(w, "mdc_chain_height", "Number of the last block.", last.Num)
+	}
+	writeGauge(w, "mdc_rest_submitted_pending", "Count of unconfirmed transactions submitted through REST service.", len(c.submittedPending()))
+	subs, clients := c.subs.stats()
+	writeGauge(w, "mdc_rest_subscriptions", "Active WebSocket/SSE subscriptions.", subs)
+	writeGauge(w, "mdc_rest_subscription_clients", "Client IPs having active subscriptions.", clients)
+}
+
+var reRouteParam = regexp.MustCompile(`\([^)]*\)`)
+
+// routeLabel returns route of request path pattern for metrics (params are replaced with "*")
+func routeLabel(re *regexp.Regexp) string {
+	s := reRouteParam.ReplaceAllString(re.String(), "*")
+	return s[1 : len(s)-1] // trim ^ $
+}
+
+// routeName returns route of request for metrics.
+// Requests aborted before routing (e.g. by rate limiter) have route "other" except write endpoints.
+func (c *Context) routeName() string {
+	if c.route != "" {
+		return c.route
+	}
+	if writeEndpoints[c.uriPath] {
+		return c.uriPath
+	}
+	return "other"
+}
+
+// statusWriter remembers http-status of response
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns underlying writer (used by http.ResponseController)
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		w.status = http.StatusSwitchingProtocols
+		return h.Hijack()
+	}
+	return nil, nil, fmt.Errorf("http-hijacking is not supported")
+}
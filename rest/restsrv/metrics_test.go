@@ -0,0 +1,38 @@
+package restsrv
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_observeRequest(t *testing.T) {
+	m := newMetrics()
+
+	m.observeRequest("/info", 200, 3*time.Millisecond)
+	m.observeRequest("/info", 200, 300*time.Millisecond)
+	m.observeRequest("/put-tx", 429, time.Millisecond)
+	m.observePutTx(false)
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	m.write(w)
+	w.Flush()
+	out := buf.String()
+
+	assert.EqualValues(t, 2, m.requestsCount("/info", 200))
+	assert.EqualValues(t, 1, m.requestsCount("/put-tx", 429))
+	assert.Contains(t, out, `mdc_rest_requests_total{route="/info",status="200"} 2`)
+	assert.Contains(t, out, `mdc_rest_request_duration_seconds_bucket{route="/info",le="0.005"} 1`)
+	assert.Contains(t, out, `mdc_rest_request_duration_seconds_bucket{route="/info",le="0.5"} 2`)
+	assert.Contains(t, out, `mdc_rest_request_duration_seconds_count{route="/info"} 2`)
+	assert.Contains(t, out, `mdc_rest_put_tx_total{result="failure"} 1`)
+}
+
+func TestRouteLabel(t *testing.T) {
+	assert.Equal(t, "/block/*", routeLabel(rePathBlockNum))
+	assert.Equal(t, "/tx/*/output/*/spent-by", routeLabel(reTxOutSpentBy))
+}
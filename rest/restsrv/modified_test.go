@@ -0,0 +1,39 @@
+package restsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_writeModified(t *testing.T) {
+	blockTs := time.Date(2019, 5, 1, 12, 0, 0, 500e6, time.UTC)
+	info := func() (interface{}, error) { return "info", nil }
+
+	// first fetch
+	c, rw := newTestContext(httptest.NewRequest("GET", "/info", nil))
+	c.writeModified(blockTs, info)
+	lastModified := rw.Header().Get("Last-Modified")
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "Wed, 01 May 2019 12:00:00 GMT", lastModified)
+
+	// no new blocks
+	req := httptest.NewRequest("GET", "/info", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	c, rw = newTestContext(req)
+	c.writeModified(blockTs, info)
+
+	assert.Equal(t, http.StatusNotModified, rw.Code)
+	assert.Equal(t, 0, rw.Body.Len())
+
+	// new block is committed
+	c, rw = newTestContext(req)
+	c.writeModified(blockTs.Add(5*time.Second), info)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, `"info"`, rw.Body.String())
+}
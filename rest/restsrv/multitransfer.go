@@ -0,0 +1,134 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+)
+
+const maxTransferOutputs = 100 // max count of recipients of /new-multi-transfer
+
+var (
+	errNoOutputs      = errors.New("400 - Empty list of outputs")
+	errTooManyOutputs = fmt.Errorf("400 - Too many outputs (max %d)", maxTransferOutputs)
+	errInvalidOutputs = errors.New("400 - Invalid outputs: JSON array of {address, memo, amount} expected")
+)
+
+// transferOutput is recipient of /new-multi-transfer; memo and amount are JSON numbers or strings
+type transferOutput struct {
+	Address string          `json:"address"`
+	Memo    json.RawMessage `json:"memo"`
+	Amount  json.RawMessage `json:"amount"`
+}
+
+func rawJSONString(v json.RawMessage) string {
+	return strings.Trim(strings.TrimSpace(string(v)), `"`)
+}
+
+// getTransferOutputs returns outputs of transfer by param "outputs" (JSON array).
+// Invalid recipients are rejected with 400 listing all of them.
+func (c *Context) getTransferOutputs(asset []byte) (outs []*txobj.TransferOutput, total bignum.Int) {
+	var list []*transferOutput
+	var data []byte
+	if c.bodyJSON != nil { // numbers of JSON body are taken as is
+		var body struct {
+			Outputs json.RawMessage `json:"outputs"`
+		}
+		if err := json.Unmarshal(c.bodyJSON, &body); err != nil {
+			c.assert(errInvalidOutputs)
+		}
+		data = body.Outputs
+	}
+	if data == nil {
+		data = []byte(c.getStr("outputs", ""))
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		c.assert(errInvalidOutputs)
+	}
+	if len(list) == 0 {
+		c.assert(errNoOutputs)
+	}
+	if len(list) > maxTransferOutputs {
+		c.assert(errTooManyOutputs)
+	}
+	decimals := c.amountDecimals()
+	total = bignum.NewInt(0)
+	var invalid []string
+	for i, o := range list {
+		addr, memo, err := c.addressByStr(strings.TrimSpace(o.Address))
+		if err == nil && len(o.Memo) > 0 && rawJSONString(o.Memo) != "" {
+			memo, err = strconv.ParseUint(rawJSONString(o.Memo), 0, 64)
+		}
+		var amount bignum.Int
+		if err == nil {
+			amount, err = parseAmount(rawJSONString(o.Amount), decimals)
+		}
+		if err == nil && amount.Sign() <= 0 {
+			err = errZeroAmount
+		}
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("#%d %q: %v", i, o.Address, strings.TrimPrefix(err.Error(), "400 - ")))
+			continue
+		}
+		outs = append(outs, &txobj.TransferOutput{Asset: asset, Amount: amount, To: addr, ToMemo: memo})
+		total = total.Add(amount)
+	}
+	if len(invalid) > 0 {
+		c.assert(errors.New("400 - Invalid recipients: " + strings.Join(invalid, "; ")))
+	}
+	return
+}
+
+// newMultiTransfer returns signed transaction of /new-multi-transfer with output for every recipient,
+// verified against balance of sender (amounts + fee)
+func (c *Context) newMultiTransfer() *chain.Transaction {
+	prvKey := c.getPrivateKey()        // private key OR seed
+	asset := c.getAsset("asset")       // asset (by default MDC)
+	comment := c.getStr("comment", "") // comment (by default "")
+	nonce := c.getNonce()              // nonce (by default 0)
+	outs, amount := c.getTransferOutputs(asset)
+
+	tx := chain.NewTx(c.bc, prvKey, nonce, &txobj.SimpleTransfer{
+		Comment: comment,
+		Outs:    outs,
+	})
+	sender := prvKey.PublicKey().Address()
+	fee := txFee(c.bc.Cfg, tx)
+	info, err := c.bc.AddressInfo(sender, 0, asset)
+	c.assert(err)
+	feeBalance := info.Balance
+	if !bytes.Equal(asset, feeAsset) {
+		feeInfo, err := c.bc.AddressInfo(sender, 0, feeAsset)
+		c.assert(err)
+		feeBalance = feeInfo.Balance
+	}
+	c.assert(checkTransferBalance(asset, amount, fee, info.Balance, feeBalance))
+	c.assertTx(tx.Verify(c.bc.Cfg))
+	c.assertFeeRate(tx)
+	return tx
+}
+
+// checkTransferBalance returns 422 if balance of asset doesn't cover amount or balance of fee asset doesn't cover fee
+// (if asset is the fee asset, fee is paid from the same balance)
+func checkTransferBalance(asset []byte, amount, fee, balance, feeBalance bignum.Int) error {
+	if bytes.Equal(asset, feeAsset) {
+		if required := amount.Add(fee); balance.Cmp(required) < 0 {
+			return fmt.Errorf("422 - Insufficient balance: %s required (amounts %s + fee %s), balance %s", required, amount, fee, balance)
+		}
+		return nil
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("422 - Insufficient balance: %s required, balance %s", amount, balance)
+	}
+	if feeBalance.Cmp(fee) < 0 {
+		return fmt.Errorf("422 - Insufficient balance of fee asset: fee %s required, balance %s", fee, feeBalance)
+	}
+	return nil
+}
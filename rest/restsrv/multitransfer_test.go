@@ -0,0 +1,68 @@
+package restsrv
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_getTransferOutputs_InvalidRecipients(t *testing.T) {
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().StrAddress()
+	outputs := `[{"address":"` + to + `","amount":1},{"address":"bad","amount":1},{"address":"` + to + `","amount":0}]`
+	c, rw := newTestAssetsContext(t, "/new-multi-transfer?outputs="+url.QueryEscape(outputs))
+
+	assert.Panics(t, func() { c.getTransferOutputs(assets.MDC) })
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Contains(t, rw.Body.String(), "Invalid recipients")
+	assert.Contains(t, rw.Body.String(), `#1 \"bad\"`)
+	assert.Contains(t, rw.Body.String(), "#2 ")
+	assert.NotContains(t, rw.Body.String(), "#0 ")
+}
+
+func TestContext_getTransferOutputs_TooMany(t *testing.T) {
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().StrAddress()
+	outs := make([]string, maxTransferOutputs+1)
+	for i := range outs {
+		outs[i] = fmt.Sprintf(`{"address":"%s","amount":%d}`, to, i+1)
+	}
+	c, rw := newTestAssetsContext(t, "/new-multi-transfer?outputs="+url.QueryEscape("["+strings.Join(outs, ",")+"]"))
+
+	assert.Panics(t, func() { c.getTransferOutputs(assets.MDC) })
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Contains(t, rw.Body.String(), errTooManyOutputs.Error()[len("400 - "):])
+}
+
+func TestContext_getTransferOutputs_Total(t *testing.T) {
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().StrAddress()
+	outputs := `[{"address":"` + to + `","amount":1},{"address":"` + to + `","memo":"7","amount":"2"}]`
+	c, _ := newTestAssetsContext(t, "/new-multi-transfer?outputs="+url.QueryEscape(outputs))
+
+	outs, total := c.getTransferOutputs(assets.MDC)
+
+	assert.Len(t, outs, 2)
+	assert.EqualValues(t, 7, outs[1].ToMemo)
+	assert.Equal(t, "3", total.String())
+}
+
+func TestCheckTransferBalance(t *testing.T) {
+	usd := []byte{0x0a, 0x0b, 0x0c}
+	n := bignum.NewInt
+
+	assert.NoError(t, checkTransferBalance(feeAsset, n(90), n(10), n(100), n(0)))
+	assert.Error(t, checkTransferBalance(feeAsset, n(91), n(10), n(100), n(100))) // fee is paid from the same balance
+	assert.NoError(t, checkTransferBalance(usd, n(100), n(10), n(100), n(10)))
+	assert.Error(t, checkTransferBalance(usd, n(101), n(10), n(100), n(10)))
+
+	err := checkTransferBalance(usd, n(100), n(10), n(100), n(9))
+	assert.Equal(t, 422, statusForError(err))
+	assert.Contains(t, err.Error(), "fee asset")
+}
@@ -0,0 +1,54 @@
+package restsrv
+
+import (
+	"fmt"
+
+	"github.com/mediacoin-pro/core/chain"
+)
+
+type networkThroughput struct {
+	Window       uint64  `json:"window"`
+	FromBlock    uint64  `json:"from_block"`
+	ToBlock      uint64  `json:"to_block"`
+	CountTxs     int     `json:"count_txs"`
+	TPS          float64 `json:"tps"`            // transactions per second
+	AvgBlockTime float64 `json:"avg_block_time"` // in seconds
+}
+
+// networkThroughput handles /network/throughput?window=<count-blocks>
+func (c *Context) networkThroughput() *networkThroughput {
+	window := c.getUint("window")
+	if window == 0 {
+		window = 100
+	} else if window > c.cfg.MaxBlocksWindow {
+		c.assert(fmt.Errorf("400 - Window is too large (max %d blocks)", c.cfg.MaxBlocksWindow))
+	}
+	last := c.bc.LastBlock()
+	if last == nil {
+		return &networkThroughput{}
+	}
+	res := &networkThroughput{ToBlock: last.Num}
+	if last.Num+1 > window {
+		res.FromBlock = last.Num + 1 - window
+	}
+	res.Window = res.ToBlock - res.FromBlock + 1
+
+	var first *chain.Block
+	c.scanBlocks(res.FromBlock, res.ToBlock, func(b *chain.Block) {
+		if first == nil {
+			first = b
+		} else {
+			res.CountTxs += len(b.Txs) // transactions of the first block were included before the measured interval
+		}
+	})
+	if first == nil || first.Num == last.Num {
+		return res
+	}
+	seconds := blockTime(last.Timestamp).Sub(blockTime(first.Timestamp)).Seconds()
+	if seconds <= 0 {
+		return res
+	}
+	res.TPS = float64(res.CountTxs) / seconds
+	res.AvgBlockTime = seconds / float64(last.Num-first.Num)
+	return res
+}
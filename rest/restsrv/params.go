@@ -0,0 +1,73 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/url"
+)
+
+const maxBodyParamsSize = 1 << 20
+
+var errInvalidJSONBody = errors.New("400 - Invalid JSON body: object of params expected")
+
+// parseBodyParams returns params of POST/PUT request body (JSON object or form-encoded).
+// Secrets (seed, login, password, private) should be sent in body so they never appear in URL.
+func (c *Context) parseBodyParams() (url.Values, error) {
+	if ct, _, _ := mime.ParseMediaType(c.req.Header.Get("Content-Type")); ct == "application/json" {
+		data, err := ioutil.ReadAll(io.LimitReader(c.req.Body, maxBodyParamsSize))
+		if err != nil {
+			return nil, err
+		}
+		c.bodyJSON = data
+		var obj map[string]interface{}
+		if len(bytes.TrimSpace(data)) > 0 && decodeJSONNumbers(data, &obj) != nil {
+			return nil, errInvalidJSONBody
+		}
+		params := url.Values{}
+		for k, v := range obj {
+			switch val := v.(type) {
+			case string:
+				params.Set(k, val)
+			case nil:
+			case json.Number: // as is (amounts and nonces don't fit float64)
+				params.Set(k, val.String())
+			case bool:
+				params.Set(k, fmt.Sprint(val))
+			default:
+				data, _ := json.Marshal(val)
+				params.Set(k, string(data))
+			}
+		}
+		return params, nil
+	}
+	if err := c.req.ParseForm(); err != nil {
+		return nil, err
+	}
+	return c.req.PostForm, nil
+}
+
+// decodeJSONNumbers decodes JSON value keeping numbers as json.Number
+func decodeJSONNumbers(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errInvalidJSONBody // trailing data
+	}
+	return nil
+}
+
+// param returns value of request param. Body params of POST/PUT request take precedence over URL query params.
+func (c *Context) param(name string) string {
+	if v := c.bodyParams.Get(name); v != "" {
+		return v
+	}
+	return c.reqQuery.Get(name)
+}
@@ -0,0 +1,38 @@
+package restsrv
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_parseBodyParams_JSONNumbers(t *testing.T) {
+	req := httptest.NewRequest("POST", "/new-transfer", strings.NewReader(`{"amount":1000000,"nonce":12345678901234567890}`))
+	req.Header.Set("Content-Type", "application/json")
+	c, _ := newTestContext(req)
+
+	assert.NoError(t, c.bodyErr)
+	assert.Equal(t, "1000000", c.getAmount("amount").String())
+	assert.Equal(t, uint64(12345678901234567890), c.getNonce())
+}
+
+func TestContext_parseBodyParams_JSONTrailingData(t *testing.T) {
+	req := httptest.NewRequest("POST", "/new-transfer", strings.NewReader(`{"amount":1} {}`))
+	req.Header.Set("Content-Type", "application/json")
+	c, _ := newTestContext(req)
+
+	assert.Equal(t, errInvalidJSONBody, c.bodyErr)
+}
+
+func TestContext_getBool(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("POST", "/new-transfer?dry_run=false&encrypt_comment=1&flag&bad=yes", nil))
+
+	assert.False(t, c.getBool("dry_run"))
+	assert.True(t, c.getBool("encrypt_comment"))
+	assert.True(t, c.getBool("flag"))
+	assert.False(t, c.getBool("missing"))
+	assert.Panics(t, func() { c.getBool("bad") })
+	assert.Equal(t, 400, rw.Code)
+}
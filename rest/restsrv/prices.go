@@ -0,0 +1,93 @@
+package restsrv
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+)
+
+// PriceOracle is source of historical asset prices
+type PriceOracle interface {
+	// Price returns price of one coin of asset in currency (e.g. "USD") at time t, or false if price is unknown
+	Price(asset []byte, currency string, t time.Time) (price float64, ok bool)
+}
+
+var errNoPriceOracle = errors.New("501 - Price oracle is not configured")
+
+type pricedTx struct {
+	*chain.Transaction
+	PriceAsset string   `json:"price_asset"`
+	Price      *float64 `json:"price"` // price of coin at block time (null if unknown)
+	Value      *float64 `json:"value"` // amount received(+)/sent(-) by address in price asset (null if price unknown)
+}
+
+// priceTxs annotates transactions of address with asset price at transaction block time
+func (c *Context) priceTxs(txs []*chain.Transaction, asset, addr []byte, memo uint64, currency string) []*pricedTx {
+	if c.PriceOracle == nil {
+		c.abort(errNoPriceOracle, 501)
+	}
+	res := make([]*pricedTx, len(txs))
+	for i, tx := range txs {
+		p := &pricedTx{Transaction: tx, PriceAsset: currency}
+		if price, ok := c.PriceOracle.Price(asset, currency, blockTime(tx.BlockTs)); ok {
+			in, out := txTransferAmounts(tx, asset, addr, memo)
+			amount, _ := strconv.ParseFloat(formatCoins(in.Sub(out)), 64)
+			value := amount * price
+			p.Price, p.Value = &price, &value
+		}
+		res[i] = p
+	}
+	return res
+}
+
+type assetValue struct {
+	Asset   string     `json:"asset"`
+	Balance bignum.Int `json:"balance"`
+	Price   float64    `json:"price,omitempty"`
+	Value   float64    `json:"value,omitempty"`
+}
+
+type totalValue struct {
+	Address  string        `json:"address"`
+	Currency string        `json:"currency"`
+	Total    float64       `json:"total"`
+	Assets   []*assetValue `json:"assets"`   // priced assets
+	Unpriced []*assetValue `json:"unpriced"` // assets without known price (not included into total)
+}
+
+// totalValue handles /address/<address>/total-value?in=<currency>
+func (c *Context) totalValue(addr []byte, memo uint64) *totalValue {
+	if c.PriceOracle == nil {
+		c.abort(errNoPriceOracle, 501)
+	}
+	res := &totalValue{
+		Address:  crypto.EncodeAddress(addr, memo),
+		Currency: c.getStr("in", "USD"),
+		Assets:   []*assetValue{},
+		Unpriced: []*assetValue{},
+	}
+	now := time.Now()
+	for _, asset := range c.knownAssets() {
+		info, err := c.bc.AddressInfo(addr, memo, asset)
+		c.assert(err)
+		if info.Balance.IsZero() {
+			continue
+		}
+		v := &assetValue{Asset: hex.EncodeToString(asset), Balance: info.Balance}
+		price, ok := c.PriceOracle.Price(asset, res.Currency, now)
+		if !ok {
+			res.Unpriced = append(res.Unpriced, v)
+			continue
+		}
+		amount, _ := strconv.ParseFloat(formatCoins(info.Balance), 64)
+		v.Price, v.Value = price, amount*price
+		res.Total += v.Value
+		res.Assets = append(res.Assets, v)
+	}
+	return res
+}
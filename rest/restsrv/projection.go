@@ -0,0 +1,79 @@
+package restsrv
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+)
+
+// ProjectionProfiles are named sets of response fields by endpoint, e.g. {"mobile": {"/block": ["num","hash"]}}.
+// Endpoint is the first segment of request path ("/block", "/txs", "/address", ...).
+type ProjectionProfiles map[string]map[string][]string
+
+// LoadProjectionProfiles reads projection profiles from JSON-file
+func LoadProjectionProfiles(filename string) (p ProjectionProfiles, err error) {
+	data, err := ioutil.ReadFile(filename)
+	if err == nil {
+		err = json.Unmarshal(data, &p)
+	}
+	return
+}
+
+// endpoint returns the first segment of request path
+func (c *Context) endpoint() string {
+	if i := strings.IndexByte(c.uriPath[1:], '/'); i >= 0 {
+		return c.uriPath[:i+1]
+	}
+	return c.uriPath
+}
+
+// projection returns fields of response selected by param "profile" (nil for full response)
+func (c *Context) projection() map[string]bool {
+	name := c.getStr("profile", "")
+	if name == "" || c.Profiles == nil {
+		return nil
+	}
+	fields, ok := c.Profiles[name][c.endpoint()]
+	if !ok {
+		return nil
+	}
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		set[f] = true
+	}
+	return set
+}
+
+// project returns JSON-object with the selected fields only (items of Response are projected, envelope is kept)
+func project(v interface{}, fields map[string]bool) interface{} {
+	if r, ok := v.(*Response); ok {
+		rr := *r
+		rr.Results = project(r.Results, fields)
+		return &rr
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var obj interface{}
+	if json.Unmarshal(data, &obj) != nil {
+		return v
+	}
+	return projectValue(obj, fields)
+}
+
+func projectValue(obj interface{}, fields map[string]bool) interface{} {
+	switch val := obj.(type) {
+	case []interface{}:
+		for i, item := range val {
+			val[i] = projectValue(item, fields)
+		}
+	case map[string]interface{}:
+		for k := range val {
+			if !fields[k] {
+				delete(val, k)
+			}
+		}
+	}
+	return obj
+}
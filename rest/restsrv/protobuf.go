@@ -0,0 +1,130 @@
+package restsrv
+
+import (
+	"io/ioutil"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/common/bin"
+	"github.com/mediacoin-pro/core/crypto"
+)
+
+const contentTypeProtobuf = "application/x-protobuf"
+
+// protoMessage is protobuf wire-format encoder of messages described in rest.proto
+type protoMessage []byte
+
+func (m *protoMessage) key(field, wireType int) {
+	m.rawVarint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (m *protoMessage) rawVarint(v uint64) {
+	for v >= 0x80 {
+		*m = append(*m, byte(v)|0x80)
+		v >>= 7
+	}
+	*m = append(*m, byte(v))
+}
+
+func (m *protoMessage) uint(field int, v uint64) {
+	if v != 0 {
+		m.key(field, 0)
+		m.rawVarint(v)
+	}
+}
+
+func (m *protoMessage) int(field int, v int64) {
+	m.uint(field, uint64(v))
+}
+
+func (m *protoMessage) bytes(field int, data []byte) {
+	if len(data) != 0 {
+		m.key(field, 2)
+		m.rawVarint(uint64(len(data)))
+		*m = append(*m, data...)
+	}
+}
+
+func (m *protoMessage) string(field int, s string) {
+	m.bytes(field, []byte(s))
+}
+
+func (m *protoMessage) message(field int, sub protoMessage) {
+	m.key(field, 2)
+	m.rawVarint(uint64(len(sub)))
+	*m = append(*m, sub...)
+}
+
+func binaryEncode(v interface{}) []byte {
+	data, _ := ioutil.ReadAll(bin.NewBuffer(nil, v))
+	return data
+}
+
+func protoTransaction(tx *chain.Transaction) (m protoMessage) {
+	m.bytes(1, tx.Hash())
+	m.uint(2, tx.ID())
+	m.int(3, int64(tx.Type))
+	m.uint(4, tx.Nonce)
+	if tx.Sender != nil {
+		m.string(5, tx.Sender.String())
+	}
+	m.uint(6, tx.BlockNum)
+	m.int(7, tx.BlockTs)
+	m.bytes(8, binaryEncode(tx))
+	return
+}
+
+func protoBlock(b *chain.Block) (m protoMessage) {
+	m.uint(1, b.Num)
+	m.bytes(2, b.Hash())
+	m.bytes(3, b.PrevHash)
+	m.int(4, b.Timestamp)
+	m.bytes(5, b.TxRoot)
+	for _, tx := range b.Txs {
+		m.message(6, protoTransaction(tx))
+	}
+	return
+}
+
+func protoAddressInfo(info *chain.AddressInfo) (m protoMessage) {
+	m.string(1, crypto.EncodeAddress(info.Address, info.Memo))
+	m.uint(2, info.Memo)
+	m.bytes(3, info.Asset)
+	m.string(4, info.Balance.String())
+	return
+}
+
+// protoResponse encodes result object as protobuf Response message
+func protoResponse(v interface{}, errMsg string) (m protoMessage) {
+	var nextOffset, nextCursor string
+	var total uint64
+	if r, ok := v.(*Response); ok {
+		v, nextOffset, nextCursor, errMsg = r.Results, r.NextOffset, r.NextCursor, r.Error
+		if r.Total != nil {
+			total = *r.Total
+		}
+	}
+	switch obj := v.(type) {
+	case nil:
+	case *chain.Block:
+		m.message(1, protoBlock(obj))
+	case []*chain.Block:
+		for _, b := range obj {
+			m.message(1, protoBlock(b))
+		}
+	case *chain.Transaction:
+		m.message(2, protoTransaction(obj))
+	case []*chain.Transaction:
+		for _, tx := range obj {
+			m.message(2, protoTransaction(tx))
+		}
+	case *chain.AddressInfo:
+		m.message(3, protoAddressInfo(obj))
+	default:
+		m.bytes(4, binaryEncode(v))
+	}
+	m.string(5, nextOffset)
+	m.string(6, errMsg)
+	m.uint(7, total)
+	m.string(8, nextCursor)
+	return
+}
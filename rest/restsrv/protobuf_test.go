@@ -0,0 +1,15 @@
+package restsrv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtoResponse_Pagination(t *testing.T) {
+	total := uint64(5)
+
+	m := protoResponse(&Response{NextOffset: "a", NextCursor: "c", Total: &total}, "")
+
+	assert.Equal(t, protoMessage{0x2a, 1, 'a', 0x38, 5, 0x42, 1, 'c'}, m)
+}
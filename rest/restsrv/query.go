@@ -0,0 +1,75 @@
+package restsrv
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mediacoin-pro/core/chain"
+)
+
+var (
+	errQueryTimeout  = errors.New("504 - Query timeout")
+	errQueryCanceled = errors.New("503 - Request canceled")
+)
+
+type queryResult struct {
+	v   interface{}
+	err error
+	pnc interface{} // panic of query
+}
+
+// runQuery runs chain query fn until request is canceled (client disconnected) or Config.QueryTimeout expires.
+// Chain API doesn't accept context, so query is run in goroutine and its result is abandoned on cancellation;
+// the request is aborted with 504 (timeout) or 503 (canceled). fn must not write response.
+func (c *Context) runQuery(fn func() (interface{}, error)) (interface{}, error) {
+	ctx := c.req.Context()
+	if timeout := c.cfg.QueryTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	done := make(chan *queryResult, 1)
+	go func() {
+		res := &queryResult{}
+		defer func() {
+			res.pnc = recover()
+			done <- res
+		}()
+		res.v, res.err = fn()
+	}()
+	select {
+	case res := <-done:
+		if res.pnc != nil {
+			panic(res.pnc)
+		}
+		return res.v, res.err
+	case <-ctx.Done():
+		err := errQueryCanceled
+		if ctx.Err() == context.DeadlineExceeded {
+			err = errQueryTimeout
+		}
+		c.abort(err, 0)
+		return nil, err
+	}
+}
+
+// getBlocks returns page of blocks (see runQuery)
+func (c *Context) getBlocks(offset uint64, limit int64, desc bool) ([]*chain.Block, error) {
+	v, err := c.runQuery(func() (interface{}, error) {
+		return c.bc.GetBlocks(offset, limit, desc)
+	})
+	blocks, _ := v.([]*chain.Block)
+	return blocks, err
+}
+
+// transactionsByAddr returns page of transactions of address and offset of the next page (see runQuery)
+func (c *Context) transactionsByAddr(asset, addr []byte, memo uint64, offset uint64, limit int64, desc bool) ([]*chain.Transaction, uint64, error) {
+	var next uint64
+	v, err := c.runQuery(func() (interface{}, error) {
+		txs, n, err := c.bc.TransactionsByAddr(asset, addr, memo, offset, limit, desc)
+		next = n
+		return txs, err
+	})
+	txs, _ := v.([]*chain.Transaction)
+	return txs, next, err
+}
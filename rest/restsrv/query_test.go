@@ -0,0 +1,68 @@
+package restsrv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runTestQuery(c *Context, fn func() (interface{}, error)) (v interface{}, aborted bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			aborted = true
+		}
+	}()
+	v, _ = c.runQuery(fn)
+	return
+}
+
+func TestContext_runQuery(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("GET", "/blocks", nil))
+
+	v, aborted := runTestQuery(c, func() (interface{}, error) { return 123, nil })
+
+	assert.False(t, aborted)
+	assert.Equal(t, 123, v)
+	assert.False(t, c.written)
+	assert.Equal(t, http.StatusOK, rw.Code)
+}
+
+func TestContext_runQuery_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c, rw := newTestContext(httptest.NewRequest("GET", "/blocks", nil).WithContext(ctx))
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	go func() {
+		<-started
+		cancel() // client disconnects while query is running
+	}()
+
+	_, aborted := runTestQuery(c, func() (interface{}, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+
+	assert.True(t, aborted)
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+}
+
+func TestContext_runQuery_timeout(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("GET", "/blocks", nil))
+	c.cfg.QueryTimeout = 10 * time.Millisecond
+	release := make(chan struct{})
+	defer close(release)
+
+	_, aborted := runTestQuery(c, func() (interface{}, error) {
+		<-release
+		return nil, nil
+	})
+
+	assert.True(t, aborted)
+	assert.Equal(t, http.StatusGatewayTimeout, rw.Code)
+	assert.Contains(t, rw.Body.String(), "504 - Query timeout")
+}
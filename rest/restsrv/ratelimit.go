@@ -0,0 +1,71 @@
+package restsrv
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+var errTooManyRequests = errors.New("429 - Too many requests")
+
+// writeEndpoints are endpoints putting transactions to mempool (limited by Server.RateLimiter)
+var writeEndpoints = map[string]bool{
+	"/put-tx":             true,
+	"/sign-and-submit":    true,
+	"/new-transfer":       true,
+	"/new-multi-transfer": true,
+	"/new-user":           true,
+}
+
+// assertRateLimit takes request token of client IP
+// and aborts request with 429-response (and header Retry-After) when the limit is exceeded.
+// Write endpoints are limited by Server.RateLimiter, other endpoints by Server.ReadRateLimiter.
+func (c *Context) assertRateLimit() {
+	limiter := c.ReadRateLimiter
+	if writeEndpoints[c.uriPath] {
+		limiter = c.RateLimiter
+	}
+	if limiter == nil {
+		return
+	}
+	if ok, retryAfter := limiter.Allow(c.clientIP()); !ok {
+		c.rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.abort(errTooManyRequests, http.StatusTooManyRequests)
+	}
+}
+
+// tunableRateLimiter is RateLimiter whose limits can be changed at runtime (e.g. by /admin/reload)
+type tunableRateLimiter interface {
+	SetRate(rate float64, burst int)
+}
+
+// applyRateLimits retunes rate limiters by changed options of cfg.
+// Options of limiters which can't be retuned keep previous values of old and are returned as requiring restart.
+func (s *Server) applyRateLimits(old, cfg *Config) (restartRequired []string) {
+	for _, l := range []struct {
+		limiter           RateLimiter
+		rateOpt, burstOpt string
+		rate, oldRate     *float64
+		burst, oldBurst   *int
+	}{
+		{s.RateLimiter, "rate-limit", "rate-burst", &cfg.RateLimit, &old.RateLimit, &cfg.RateBurst, &old.RateBurst},
+		{s.ReadRateLimiter, "read-rate-limit", "read-rate-burst", &cfg.ReadRateLimit, &old.ReadRateLimit, &cfg.ReadRateBurst, &old.ReadRateBurst},
+	} {
+		if *l.rate == *l.oldRate && *l.burst == *l.oldBurst {
+			continue
+		}
+		if t, ok := l.limiter.(tunableRateLimiter); ok {
+			t.SetRate(*l.rate, *l.burst)
+			continue
+		}
+		if *l.rate != *l.oldRate {
+			restartRequired = append(restartRequired, l.rateOpt)
+		}
+		if *l.burst != *l.oldBurst {
+			restartRequired = append(restartRequired, l.burstOpt)
+		}
+		*l.rate, *l.burst = *l.oldRate, *l.oldBurst
+	}
+	return
+}
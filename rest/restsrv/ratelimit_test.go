@@ -0,0 +1,144 @@
+package restsrv
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimitedServer() *Server {
+	s := &Server{
+		RateLimiter:     NewMemRateLimiter(1, 3),
+		ReadRateLimiter: NewMemRateLimiter(100, 10),
+	}
+	s.setConfig(&Config{})
+	return s
+}
+
+// execRateLimit returns http-status of request checked by rate limiter (200 if request is allowed)
+func execRateLimit(s *Server, path, remoteAddr, forwardedFor string) (status int, retryAfter string) {
+	req := httptest.NewRequest("GET", path, nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	rw := httptest.NewRecorder()
+	func() {
+		defer func() { recover() }()
+		newContext(s, req, rw).assertRateLimit()
+	}()
+	return rw.Code, rw.Header().Get("Retry-After")
+}
+
+func TestMemRateLimiter_Burst(t *testing.T) {
+	l := NewMemRateLimiter(10, 5)
+
+	for i := 0; i < 5; i++ {
+		ok, _ := l.Allow("1.2.3.4")
+		assert.True(t, ok)
+	}
+	ok, retryAfter := l.Allow("1.2.3.4")
+	assert.False(t, ok)
+	assert.True(t, retryAfter > 0 && retryAfter <= 100*time.Millisecond)
+
+	ok, _ = l.Allow("5.6.7.8") // other key has own bucket
+	assert.True(t, ok)
+
+	time.Sleep(120 * time.Millisecond) // one token is refilled
+	ok, _ = l.Allow("1.2.3.4")
+	assert.True(t, ok)
+	ok, _ = l.Allow("1.2.3.4")
+	assert.False(t, ok)
+}
+
+func TestMemRateLimiter_Unlimited(t *testing.T) {
+	l := NewMemRateLimiter(0, 0)
+
+	for i := 0; i < 1000; i++ {
+		ok, _ := l.Allow("1.2.3.4")
+		assert.True(t, ok)
+	}
+}
+
+func TestContext_assertRateLimit_WriteBurst(t *testing.T) {
+	s := newTestRateLimitedServer()
+
+	for i := 0; i < 3; i++ {
+		status, _ := execRateLimit(s, "/put-tx", "1.2.3.4:1000", "")
+		assert.Equal(t, http.StatusOK, status)
+	}
+	status, retryAfter := execRateLimit(s, "/put-tx", "1.2.3.4:1000", "")
+
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, "1", retryAfter)
+}
+
+func TestContext_assertRateLimit_ReadLimitIsSeparate(t *testing.T) {
+	s := newTestRateLimitedServer()
+	for i := 0; i < 4; i++ {
+		execRateLimit(s, "/new-transfer", "1.2.3.4:1000", "")
+	}
+
+	status, _ := execRateLimit(s, "/info", "1.2.3.4:1000", "")
+
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestContext_assertRateLimit_ForwardedFor(t *testing.T) {
+	s := newTestRateLimitedServer()
+	s.setConfig(&Config{TrustProxy: true})
+
+	for i := 0; i < 3; i++ {
+		execRateLimit(s, "/new-user", "10.0.0.1:1000", "1.1.1.1")
+	}
+	status1, _ := execRateLimit(s, "/new-user", "10.0.0.1:1000", "1.1.1.1")
+	status2, _ := execRateLimit(s, "/new-user", "10.0.0.1:1000", "2.2.2.2")
+
+	assert.Equal(t, http.StatusTooManyRequests, status1)
+	assert.Equal(t, http.StatusOK, status2)
+}
+
+// countAllowed returns count of requests of client IP allowed before the first 429-response
+func countAllowed(s *Server, path, remoteAddr string) (n int) {
+	for ; n < 100; n++ {
+		if status, _ := execRateLimit(s, path, remoteAddr, ""); status == http.StatusTooManyRequests {
+			break
+		}
+	}
+	return
+}
+
+func TestContext_adminReload_RateLimit(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "node.conf")
+	os.WriteFile(cfgFile, []byte("rate-burst=5\nread-rate-burst=2\n"), 0600)
+	s := newTestRateLimitedServer()
+	s.setConfig(&Config{RateLimit: 1, RateBurst: 3, ReadRateLimit: 100, ReadRateBurst: 10, ConfigFile: cfgFile})
+	before := countAllowed(s, "/put-tx", "1.2.3.4:1000")
+
+	newContext(s, httptest.NewRequest("POST", "/admin/reload", nil), httptest.NewRecorder()).adminReload()
+
+	assert.Equal(t, 3, before)
+	assert.Equal(t, 5, countAllowed(s, "/put-tx", "5.6.7.8:1000"))
+	assert.Equal(t, 2, countAllowed(s, "/info", "5.6.7.8:1000"))
+	assert.Equal(t, 5, s.config().RateBurst)
+}
+
+func TestContext_adminReload_RateLimitOfCustomLimiter(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "node.conf")
+	os.WriteFile(cfgFile, []byte("rate-limit=5\n"), 0600)
+	s := newTestRateLimitedServer()
+	s.RateLimiter = struct{ RateLimiter }{s.RateLimiter} // limiter without SetRate
+	s.setConfig(&Config{RateLimit: 1, RateBurst: 3, ConfigFile: cfgFile})
+
+	res := newContext(s, httptest.NewRequest("POST", "/admin/reload", nil), httptest.NewRecorder()).adminReload()
+
+	data, _ := json.Marshal(res)
+	assert.Contains(t, string(data), `"restart_required":["rate-limit"]`)
+	assert.Equal(t, 1.0, s.config().RateLimit)
+}
@@ -0,0 +1,47 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/mediacoin-pro/core/common/xlog"
+)
+
+var errInternal = errors.New("500 - Internal server error")
+
+// abortedRequest is panic of request aborted by c.abort after error response is written
+type abortedRequest struct {
+	error
+}
+
+// abort writes error response and aborts request handling (c.assert and similar helpers)
+func (c *Context) abort(err error, httpCode int) {
+	c.WriteError(err, httpCode)
+	panic(abortedRequest{err})
+}
+
+// recoverPanic catches panic of request handler.
+// Aborted requests (c.abort) are only traced; other panics (runtime errors as well) are logged with request and stack,
+// client gets 500 if response isn't written yet. Idempotency-Key reserved by request is released.
+func (c *Context) recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	c.releaseIdempotent()
+	if a, ok := r.(abortedRequest); ok {
+		xlog.Trace.Printf("rest> [%s] request aborted: %s %s from %s: %v", c.reqID, c.req.Method, c.req.URL.Path, c.req.RemoteAddr, a.error)
+		return
+	}
+	xlog.Error.Printf("rest> [%s] PANIC: %s %s from %s: %v\n%s", c.reqID, c.req.Method, c.req.URL.Path, c.req.RemoteAddr, r, debug.Stack())
+	if !c.written {
+		c.WriteError(errInternal, http.StatusInternalServerError)
+	}
+}
+
+// writeHeader writes http-status of response
+func (c *Context) writeHeader(httpCode int) {
+	c.written = true
+	c.rw.WriteHeader(httpCode)
+}
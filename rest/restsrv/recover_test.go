@@ -0,0 +1,42 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func execWithRecover(fn func(c *Context)) *httptest.ResponseRecorder {
+	c, rw := newTestContext(httptest.NewRequest("GET", "/info", nil))
+	func() {
+		defer c.recoverPanic()
+		fn(c)
+	}()
+	return rw
+}
+
+func TestContext_recoverPanic(t *testing.T) {
+	rw := execWithRecover(func(c *Context) { panic("unexpected") })
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Contains(t, rw.Body.String(), `"error":"500 - Internal server error"`)
+}
+
+func TestContext_recoverPanic_afterAssert(t *testing.T) {
+	rw := execWithRecover(func(c *Context) { c.assert(errors.New("400 - Invalid param")) })
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	assert.Equal(t, `{"error":"400 - Invalid param"}`, rw.Body.String())
+}
+
+func TestContext_recoverPanic_runtimeError(t *testing.T) {
+	rw := execWithRecover(func(c *Context) {
+		var m map[string]int
+		m["x"]++ // runtime error isn't taken for aborted request
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+}
@@ -0,0 +1,97 @@
+package restsrv
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rejectionsBufferSize = 1000
+
+// rejection is event of rejected transaction submission (without transaction content)
+type rejection struct {
+	Time     time.Time `json:"time"`
+	Endpoint string    `json:"endpoint"`
+	ClientIP string    `json:"client_ip"`
+	ReqID    string    `json:"request_id"`
+	Reason   string    `json:"reason"`
+}
+
+// rejections is ring buffer of the last rejection events
+type rejections struct {
+	mx     sync.Mutex
+	events []*rejection
+	next   int
+}
+
+func newRejections(size int) *rejections {
+	return &rejections{events: make([]*rejection, 0, size)}
+}
+
+func (r *rejections) add(e *rejection) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if len(r.events) < cap(r.events) {
+		r.events = append(r.events, e)
+	} else {
+		r.events[r.next] = e
+	}
+	r.next = (r.next + 1) % cap(r.events)
+}
+
+// last returns the newest events first
+func (r *rejections) last(limit int) []*rejection {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	n := len(r.events)
+	if limit > n {
+		limit = n
+	}
+	res := make([]*rejection, 0, limit)
+	for i := 1; i <= limit; i++ {
+		res = append(res, r.events[(r.next-i+n)%n])
+	}
+	return res
+}
+
+// clientIP returns IP address of client.
+// Behind reverse proxy (Config.TrustProxy) it's the last address of header X-Forwarded-For added by the proxy.
+func (c *Context) clientIP() string {
+	if c.cfg.TrustProxy {
+		if ff := c.req.Header.Get("X-Forwarded-For"); ff != "" {
+			ips := strings.Split(ff, ",")
+			if ip := strings.TrimSpace(ips[len(ips)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(c.req.RemoteAddr)
+	if err != nil {
+		return c.req.RemoteAddr
+	}
+	return host
+}
+
+// assertTx aborts transaction submission and records rejection if transaction is not admissible
+func (c *Context) assertTx(err error) {
+	if err != nil {
+		if _, ok := errorStatus(err); !ok { // error of tx.Verify
+			err = fmt.Errorf("400 - Invalid transaction: %w", err)
+		}
+		c.recordRejection(err)
+		c.assert(err)
+	}
+}
+
+func (c *Context) recordRejection(err error) {
+	c.metrics.observePutTx(false)
+	c.rejections.add(&rejection{
+		Time:     time.Now().UTC(),
+		Endpoint: c.uriPath,
+		ClientIP: c.clientIP(),
+		ReqID:    c.reqID,
+		Reason:   err.Error(),
+	})
+}
@@ -0,0 +1,43 @@
+package restsrv
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const maxRequestIDLen = 128
+
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// initRequestID takes request id from configured header (or generates new one) and echoes it in response.
+// Logs of request are prefixed with the id.
+func (c *Context) initRequestID() {
+	header := c.cfg.RequestIDHeader
+	id := ""
+	if header != "" {
+		id = c.req.Header.Get(header)
+	}
+	if !isValidRequestID(id) {
+		id = newRequestID()
+	}
+	c.reqID = id
+	if header != "" {
+		c.rw.Header().Set(header, id)
+	}
+}
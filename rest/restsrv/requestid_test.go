@@ -0,0 +1,43 @@
+package restsrv
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestContext(req *http.Request) (*Context, *httptest.ResponseRecorder) {
+	s := &Server{}
+	s.setConfig(&Config{RequestIDHeader: "X-Request-ID"})
+	rw := httptest.NewRecorder()
+	return newContext(s, req, rw), rw
+}
+
+func TestContext_initRequestID(t *testing.T) {
+	c, rw := newTestContext(httptest.NewRequest("GET", "/info", nil))
+
+	id := rw.Header().Get("X-Request-ID")
+	assert.Len(t, id, 16)
+	assert.Equal(t, id, c.reqID)
+}
+
+func TestContext_initRequestID_incoming(t *testing.T) {
+	req := httptest.NewRequest("GET", "/info", nil)
+	req.Header.Set("X-Request-ID", "wallet-7f3a.42")
+	c, rw := newTestContext(req)
+
+	assert.Equal(t, "wallet-7f3a.42", rw.Header().Get("X-Request-ID"))
+	assert.Equal(t, "wallet-7f3a.42", c.reqID)
+}
+
+func TestContext_initRequestID_invalidIncoming(t *testing.T) {
+	req := httptest.NewRequest("GET", "/info", nil)
+	req.Header.Set("X-Request-ID", "bad id")
+	_, rw := newTestContext(req)
+
+	id := rw.Header().Get("X-Request-ID")
+	assert.NotEqual(t, "bad id", id)
+	assert.Len(t, id, 16)
+}
@@ -7,9 +7,14 @@ import (
 )
 
 type Response struct {
-	Results    interface{} `json:"results,omitempty"`
-	NextOffset string      `json:"next_offset,omitempty"`
-	Error      string      `json:"error,omitempty"`
+	Results    interface{}       `json:"results,omitempty"`
+	NextOffset string            `json:"next_offset,omitempty"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	Encodings  *addressEncodings `json:"encodings,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Total      *uint64           `json:"total,omitempty"` // total count of items (omitted if it's expensive to count)
+
+	Deprecation *deprecationNotice `json:"deprecation,omitempty"` // set if request uses deprecated route or param
 }
 
 func NewResponse(res interface{}, nextOffset interface{}, err error) *Response {
@@ -25,3 +30,31 @@ func NewResponse(res interface{}, nextOffset interface{}, err error) *Response {
 	}
 	return r
 }
+
+// setTotal sets total count of items of paginated list
+func (r *Response) setTotal(n uint64) *Response {
+	r.Total = &n
+	return r
+}
+
+// pageBounds returns bounds of page of list with n items by params "offset" and "limit" and offset of the next page (0 if it's the last page)
+func (c *Context) pageBounds(n int) (from, to int, next uint64) {
+	offset, limit := c.getUint("offset"), uint64(c.getLimit())
+	if offset > uint64(n) {
+		offset = uint64(n)
+	}
+	from, to = int(offset), n
+	if uint64(n)-offset > limit {
+		to, next = int(offset+limit), offset+limit
+	}
+	return
+}
+
+// pageResponse returns response with page of list (next_offset is omitted for the last page)
+func pageResponse(page interface{}, next uint64) *Response {
+	resp := NewResponse(page, next, nil)
+	if next == 0 {
+		resp.NextOffset = ""
+	}
+	return resp
+}
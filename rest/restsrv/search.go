@@ -0,0 +1,75 @@
+package restsrv
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/mediacoin-pro/core/chain/assets"
+)
+
+var (
+	errEmptySearch   = errors.New("400 - Empty search query")
+	errNothingFound  = errors.New("404 - Nothing found: expected block number, transaction hash (64 hex digits), address (MDC..., @nick, 0x<userID>) or transaction id (1-16 hex digits)")
+	errBlockNotFound = errors.New("404 - Block not found")
+	errTxNotFound    = errors.New("404 - Transaction not found")
+)
+
+type searchResult struct {
+	Type   string      `json:"type"` // block | tx | address
+	Result interface{} `json:"result"`
+}
+
+// search handles /search?q=<block-num|tx-hash|address|tx-id>.
+// Kind of query is detected by the same patterns as paths of routes /block/<num>, /tx/<hash>, /address/<address>, /tx/<id>.
+func (c *Context) search() *searchResult {
+	q := strings.TrimSpace(c.getStr("q", ""))
+	if q == "" {
+		c.assert(errEmptySearch)
+	}
+	switch {
+	case rePathBlockNum.MatchString("/block/" + q):
+		num, err := strconv.ParseUint(q, 10, 64)
+		c.assert(err)
+		block, err := c.bc.GetBlock(num)
+		c.assert(err)
+		if block == nil {
+			c.assert(errBlockNotFound)
+		}
+		return &searchResult{"block", c.blockView(block)}
+
+	case reTxHash.MatchString("/tx/" + strings.ToLower(q)):
+		txHash, _ := hex.DecodeString(strings.ToLower(q))
+		tx, err := c.bc.TransactionByHash(txHash)
+		c.assert(err)
+		if tx != nil {
+			return &searchResult{"tx", tx}
+		}
+		block, err := c.blockByHash(txHash) // hash of block
+		c.assert(err)
+		if block != nil {
+			return &searchResult{"block", c.blockView(block)}
+		}
+		c.assert(errTxNotFound)
+
+	case rePathAddressInfo.MatchString("/address/" + q):
+		addr, memo, err := c.addressByStr(q)
+		c.assert(err)
+		info, err := c.bc.AddressInfo(addr, memo, assets.MDC)
+		c.assert(err)
+		return &searchResult{"address", &addressInfoExt{AddressInfo: info, Encodings: c.addressEncodings(addr, memo)}}
+
+	case reTxID.MatchString("/tx/" + strings.ToLower(q)):
+		txID, err := parseTxID(strings.ToLower(q))
+		c.assert(err)
+		tx, err := c.bc.TransactionByID(txID)
+		c.assert(err)
+		if tx == nil {
+			c.assert(errTxNotFound)
+		}
+		return &searchResult{"tx", tx}
+	}
+	c.assert(errNothingFound)
+	return nil
+}
@@ -1,9 +1,12 @@
 package restsrv
 
 import (
-	"fmt"
+	"context"
+	"crypto/cipher"
+	"errors"
 	"net/http"
-	"runtime/debug"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mediacoin-pro/core/chain/bcstore"
@@ -12,8 +15,52 @@ import (
 )
 
 type Server struct {
-	cfg *Config
-	bc  *bcstore.ChainStorage
+	cfg       *Config      // initial config
+	liveCfg   atomic.Value // actual config (*Config)
+	bc        *bcstore.ChainStorage
+	feed      *blockFeed
+	subs      *subscriptions
+	submitted *submittedTxs
+	index     *chainIndex
+	flights   *flightGroup
+	metrics   *metrics
+
+	rejections *rejections
+
+	cursorCipher cipher.AEAD
+
+	mx         sync.Mutex
+	httpServer *http.Server
+	active     sync.WaitGroup // requests in progress
+	closing    chan struct{}  // closed on shutdown
+	closeOnce  sync.Once
+
+	// pluggable storages (in-process by default; replace before Start for multi-instance deployments)
+	Cache           Cache
+	Idempotency     IdempotencyStore
+	RateLimiter     RateLimiter // limiter of write requests (putting transactions) by client IP
+	ReadRateLimiter RateLimiter // limiter of other requests by client IP
+
+	AssetSymbols map[string][]byte // display symbols of assets other than MDC (symbol -> asset id)
+
+	PriceOracle PriceOracle   // source of asset prices (not configured by default)
+	Network     NetworkStatus // source of peers and sync state of node (not configured by default)
+
+	Version   string // version of node software
+	startTime time.Time
+
+	Profiles ProjectionProfiles // response projection profiles (param "profile")
+
+	ClientProfiles map[string]*ClientProfile // default response options by API key
+
+	Deprecations []*Deprecation // deprecated routes and params (clients get warning headers)
+
+	CORS *CORSPolicy // policy of cross-origin requests (nil - CORS-headers are not sent)
+
+	ConfirmationPolicy *ConfirmationPolicy // recommended confirmations by payment amount (nil - DefaultConfirmationPolicy)
+
+	DefaultLimit int64 // default page size of lists (0 - defaultLimit)
+	MaxLimit     int64 // max page size of lists; larger limits are clamped (0 - maxLimit)
 }
 
 func StartServer(cfg *Config, bc *bcstore.ChainStorage) {
@@ -22,13 +69,54 @@ func StartServer(cfg *Config, bc *bcstore.ChainStorage) {
 }
 
 func NewService(cfg *Config, bc *bcstore.ChainStorage) *Server {
-	return &Server{
-		cfg: cfg,
-		bc:  bc,
+	s := &Server{
+		cfg:       cfg,
+		bc:        bc,
+		feed:      newBlockFeed(bc),
+		subs:      newSubscriptions(),
+		submitted: newSubmittedTxs(),
+		index:     newChainIndex(bc),
+		flights:   newFlightGroup(),
+		metrics:   newMetrics(),
+
+		rejections: newRejections(rejectionsBufferSize),
+
+		cursorCipher: newCursorCipher(cfg.CursorSecret),
+
+		startTime: time.Now(),
+		closing:   make(chan struct{}),
+
+		Cache:           NewMemCache(),
+		Idempotency:     NewMemIdempotencyStore(),
+		RateLimiter:     NewMemRateLimiter(cfg.RateLimit, cfg.RateBurst),
+		ReadRateLimiter: NewMemRateLimiter(cfg.ReadRateLimit, cfg.ReadRateBurst),
+
+		CORS: &CORSPolicy{Origins: []string{"*"}, WriteOrigins: []string{}}, // writes (taking seeds and keys) are same-origin only
+
+		ConfirmationPolicy: DefaultConfirmationPolicy(),
+	}
+	if cfg.ProfilesFile != "" {
+		p, err := LoadProjectionProfiles(cfg.ProfilesFile)
+		if err != nil {
+			xlog.Panic(err)
+		}
+		s.Profiles = p
 	}
+	s.setConfig(cfg)
+	return s
+}
+
+// config returns actual config
+func (s *Server) config() *Config {
+	return s.liveCfg.Load().(*Config)
+}
+
+func (s *Server) setConfig(cfg *Config) {
+	s.liveCfg.Store(cfg)
 }
 
 func (s *Server) Start() {
+	go s.index.run(s.closing)
 	server := &http.Server{
 		Addr:           s.cfg.HTTPConn,
 		Handler:        s,
@@ -36,22 +124,69 @@ func (s *Server) Start() {
 		WriteTimeout:   20 * time.Second,
 		MaxHeaderBytes: int(64 * consts.MiB),
 	}
-	if err := server.ListenAndServe(); err != nil {
+	s.mx.Lock()
+	s.httpServer = server
+	s.mx.Unlock()
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		xlog.Panic(err)
 	}
 }
 
+var errShuttingDown = errors.New("503 - Server is shutting down")
+
+// Shutdown stops accepting new connections, closes WebSocket/SSE subscriptions
+// and waits until requests in progress are finished or ctx is done.
+func (s *Server) Shutdown(ctx context.Context) (err error) {
+	s.closeOnce.Do(func() { close(s.closing) })
+	s.mx.Lock()
+	server := s.httpServer
+	s.mx.Unlock()
+	if server != nil {
+		err = server.Shutdown(ctx)
+	}
+	done := make(chan struct{})
+	go func() {
+		s.active.Wait() // including hijacked WebSocket connections
+		close(done)
+	}()
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) isClosing() bool {
+	select {
+	case <-s.closing:
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.active.Add(1)
+	defer s.active.Done()
+
+	start := time.Now()
+	sw := &statusWriter{ResponseWriter: rw}
+	ctx := newContext(s, req, sw)
 
 	defer func() {
-		if r := recover(); r != nil {
-			err := fmt.Errorf("http-PANIC: %v", r)
-			xlog.Error.Printf("http> ServeHTTP-PANIC: %v\n%s", err, string(debug.Stack()))
-			rw.WriteHeader(http.StatusInternalServerError)
-			//http.Error(rw, err.Error(), http.StatusInternalServerError)
+		status := sw.status
+		if status == 0 {
+			status = http.StatusOK
 		}
+		s.metrics.observeRequest(ctx.routeName(), status, time.Since(start))
 	}()
+	defer ctx.recoverPanic()
 
-	ctx := newContext(s, req, rw)
+	if s.isClosing() {
+		rw.Header().Set("Connection", "close")
+		ctx.WriteError(errShuttingDown, http.StatusServiceUnavailable)
+		return
+	}
 	ctx.Exec()
 }
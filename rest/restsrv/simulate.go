@@ -0,0 +1,207 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/common/bin"
+	"github.com/mediacoin-pro/core/crypto"
+)
+
+const maxSimulateTxs = 50
+
+var errInsufficientFunds = errors.New("Insufficient funds")
+
+type simulationStep struct {
+	Index int    `json:"index"`
+	Hash  string `json:"hash"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type simulatedBalance struct {
+	Address string     `json:"address"`
+	Asset   string     `json:"asset"`
+	Before  bignum.Int `json:"before"`
+	After   bignum.Int `json:"after"`
+}
+
+type simulation struct {
+	OK       bool                `json:"ok"` // all transactions would succeed
+	Steps    []*simulationStep   `json:"steps"`
+	Balances []*simulatedBalance `json:"balances"` // balances changed by successful transactions
+}
+
+// balanceOverlay keeps balances changed by simulated transactions over actual state.
+// Balance of address with memo 0 is total balance of address (including memo sub-addresses).
+type balanceOverlay struct {
+	c           *Context
+	addressInfo func(addr []byte, memo uint64, asset []byte) (*chain.AddressInfo, error) // actual state
+	chainNonce  func(sender []byte) (nonce uint64, ok bool)                              // nonce of sender in actual state
+	items       map[string]*simulatedBalance
+	keys        []string
+	nonces      map[string]uint64 // nonce of last applied transaction by sender address
+}
+
+func newBalanceOverlay(c *Context) *balanceOverlay {
+	return &balanceOverlay{
+		c:           c,
+		addressInfo: c.bc.AddressInfo,
+		chainNonce:  c.chainNonce,
+		items:       map[string]*simulatedBalance{},
+		nonces:      map[string]uint64{},
+	}
+}
+
+func (o *balanceOverlay) get(asset, addr []byte, memo uint64) *simulatedBalance {
+	key := string(asset) + ":" + string(addr) + ":" + strconv.FormatUint(memo, 10)
+	b := o.items[key]
+	if b == nil {
+		info, err := o.addressInfo(addr, memo, asset)
+		o.c.assert(err)
+		balance := bignum.NewInt(0)
+		if info != nil {
+			balance = info.Balance
+		}
+		b = &simulatedBalance{
+			Address: crypto.EncodeAddress(addr, memo),
+			Asset:   hex.EncodeToString(asset),
+			Before:  balance,
+			After:   balance,
+		}
+		o.items[key] = b
+		o.keys = append(o.keys, key)
+	}
+	return b
+}
+
+// apply applies nonce, fee and transfers of transaction to overlay; overlay is not changed if transaction fails
+func (o *balanceOverlay) apply(tx *chain.Transaction) error {
+	if tx.Sender == nil {
+		return nil
+	}
+	tr, _ := tx.TxObject().(*txobj.SimpleTransfer)
+	return o.applyTx(tx.Sender.Address(), tx.Nonce, txFee(o.c.bc.Cfg, tx), tr)
+}
+
+// applyTx applies transaction of sender (tr is nil for transactions without transfers)
+func (o *balanceOverlay) applyTx(sender []byte, nonce uint64, fee bignum.Int, tr *txobj.SimpleTransfer) error {
+	last, ok := o.nonces[string(sender)]
+	if !ok { // the first transaction of sender follows its last committed transaction
+		last, ok = o.chainNonce(sender)
+	}
+	if ok && nonce != last+1 {
+		return fmt.Errorf("Nonce %d doesn't follow nonce %d of previous transaction of sender", nonce, last)
+	}
+	required := map[string]bignum.Int{}
+	var assetList [][]byte
+	spend := func(asset []byte, amount bignum.Int) {
+		k := string(asset)
+		if _, ok := required[k]; !ok {
+			required[k] = bignum.NewInt(0)
+			assetList = append(assetList, asset)
+		}
+		required[k] = required[k].Add(amount)
+	}
+	if fee.Sign() > 0 {
+		spend(feeAsset, fee)
+	}
+	var outs []*txobj.TransferOutput
+	if tr != nil {
+		outs = tr.Outs
+	}
+	for _, out := range outs {
+		spend(out.Asset, out.Amount)
+	}
+	for _, asset := range assetList {
+		if o.get(asset, sender, 0).After.Cmp(required[string(asset)]) < 0 {
+			return errInsufficientFunds
+		}
+	}
+	if fee.Sign() > 0 {
+		s := o.get(feeAsset, sender, 0)
+		s.After = s.After.Sub(fee)
+	}
+	for _, out := range outs {
+		s := o.get(out.Asset, sender, 0)
+		s.After = s.After.Sub(out.Amount)
+		r := o.get(out.Asset, out.To, 0)
+		r.After = r.After.Add(out.Amount)
+		if out.ToMemo != 0 {
+			r = o.get(out.Asset, out.To, out.ToMemo)
+			r.After = r.After.Add(out.Amount)
+		}
+	}
+	o.nonces[string(sender)] = nonce
+	return nil
+}
+
+// chainNonce returns nonce of the last transaction of sender committed to blockchain (false if sender has no transactions)
+func (c *Context) chainNonce(sender []byte) (nonce uint64, ok bool) {
+	c.scanAddressTxs(feeAsset, sender, 0, true, maxScanTxs, func(tx *chain.Transaction) bool {
+		if tx.Sender != nil && bytes.Equal(tx.Sender.Address(), sender) {
+			nonce, ok = tx.Nonce, true
+			return false
+		}
+		return true
+	})
+	return
+}
+
+// simulateTxs handles /txs/simulate?txs=<tx:hex>,<tx:hex>,... (or binary-encoded list of transactions in request body).
+// Transactions are checked in order against actual state with effects of previous successful transactions
+// (signature, fee rate, nonces of sender following its last committed transaction and balances of transfers and fees). Real state and mempool are not changed.
+func (c *Context) simulateTxs() *simulation {
+	var txs []*chain.Transaction
+	if s := c.getStr("txs", ""); s != "" {
+		for _, h := range strings.Split(s, ",") {
+			data, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(h), "0x"))
+			c.assert(err)
+			var tx *chain.Transaction
+			c.assert(bin.Decode(data, &tx))
+			txs = append(txs, tx)
+		}
+	} else {
+		c.getBinary(&txs)
+	}
+	if len(txs) == 0 {
+		c.assert(errors.New("400 - Empty list of transactions"))
+	}
+	if len(txs) > maxSimulateTxs {
+		c.assert(fmt.Errorf("400 - Too many transactions (max %d)", maxSimulateTxs))
+	}
+	overlay := newBalanceOverlay(c)
+	res := &simulation{OK: true, Steps: []*simulationStep{}, Balances: []*simulatedBalance{}}
+	for i, tx := range txs {
+		step := &simulationStep{Index: i}
+		var err error
+		if tx == nil {
+			err = errors.New("Empty transaction")
+		} else {
+			step.Hash = hex.EncodeToString(tx.Hash())
+			if err = tx.Verify(c.bc.Cfg); err == nil {
+				if err = c.checkFeeRate(tx); err == nil {
+					err = overlay.apply(tx)
+				}
+			}
+		}
+		if step.OK = err == nil; !step.OK {
+			step.Error = err.Error()
+			res.OK = false
+		}
+		res.Steps = append(res.Steps, step)
+	}
+	for _, key := range overlay.keys {
+		if b := overlay.items[key]; b.Before.Cmp(b.After) != 0 {
+			res.Balances = append(res.Balances, b)
+		}
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package restsrv
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBalanceOverlay(balances map[string]int64) *balanceOverlay {
+	c, _ := newTestContext(httptest.NewRequest("POST", "/txs/simulate", nil))
+	return &balanceOverlay{
+		c: c,
+		addressInfo: func(addr []byte, memo uint64, asset []byte) (*chain.AddressInfo, error) {
+			return &chain.AddressInfo{Balance: bignum.NewInt(balances[string(addr)])}, nil
+		},
+		chainNonce: func(sender []byte) (uint64, bool) { return 0, false },
+		items:      map[string]*simulatedBalance{},
+		nonces:     map[string]uint64{},
+	}
+}
+
+func testTransfer(to string, amount int64) *txobj.SimpleTransfer {
+	return &txobj.SimpleTransfer{Outs: []*txobj.TransferOutput{{Asset: feeAsset, Amount: bignum.NewInt(amount), To: []byte(to)}}}
+}
+
+func TestBalanceOverlay_applyTx_FeeOfPreviousTx(t *testing.T) {
+	o := newTestBalanceOverlay(map[string]int64{"A": 100})
+
+	err1 := o.applyTx([]byte("A"), 1, bignum.NewInt(10), testTransfer("B", 50))
+	err2 := o.applyTx([]byte("A"), 2, bignum.NewInt(0), testTransfer("B", 50)) // 40 is left after the first fee
+
+	assert.NoError(t, err1)
+	assert.Equal(t, errInsufficientFunds, err2)
+	assert.Equal(t, "40", o.get(feeAsset, []byte("A"), 0).After.String())
+	assert.Equal(t, "50", o.get(feeAsset, []byte("B"), 0).After.String())
+}
+
+func TestBalanceOverlay_applyTx_FeeIsRequired(t *testing.T) {
+	o := newTestBalanceOverlay(map[string]int64{"A": 100})
+
+	err := o.applyTx([]byte("A"), 1, bignum.NewInt(1), testTransfer("B", 100))
+
+	assert.Equal(t, errInsufficientFunds, err)
+	assert.Equal(t, "100", o.get(feeAsset, []byte("A"), 0).After.String())
+}
+
+func TestBalanceOverlay_applyTx_Nonce(t *testing.T) {
+	o := newTestBalanceOverlay(map[string]int64{"A": 100, "C": 100})
+
+	err1 := o.applyTx([]byte("A"), 5, bignum.NewInt(1), testTransfer("B", 1))
+	err2 := o.applyTx([]byte("A"), 7, bignum.NewInt(1), testTransfer("B", 1))
+	err3 := o.applyTx([]byte("C"), 1, bignum.NewInt(1), testTransfer("B", 1)) // other sender
+	err4 := o.applyTx([]byte("A"), 6, bignum.NewInt(1), testTransfer("B", 1))
+
+	assert.NoError(t, err1)
+	assert.EqualError(t, err2, "Nonce 7 doesn't follow nonce 5 of previous transaction of sender")
+	assert.NoError(t, err3)
+	assert.NoError(t, err4)
+}
+
+func TestBalanceOverlay_applyTx_Memo(t *testing.T) {
+	o := newTestBalanceOverlay(map[string]int64{"A": 100})
+	tr := testTransfer("B", 30)
+	tr.Outs[0].ToMemo = 7
+
+	err := o.applyTx([]byte("A"), 1, bignum.NewInt(0), tr)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "30", o.get(feeAsset, []byte("B"), 7).After.String())
+	assert.Equal(t, "30", o.get(feeAsset, []byte("B"), 0).After.String())
+	assert.Equal(t, "0", o.get(feeAsset, []byte("B"), 8).After.String())
+}
+
+func TestBalanceOverlay_applyTx_ChainNonce(t *testing.T) {
+	o := newTestBalanceOverlay(map[string]int64{"A": 100})
+	o.chainNonce = func(sender []byte) (uint64, bool) { return 5, string(sender) == "A" }
+
+	err1 := o.applyTx([]byte("A"), 5, bignum.NewInt(1), testTransfer("B", 1)) // nonce of committed transaction
+	err2 := o.applyTx([]byte("A"), 6, bignum.NewInt(1), testTransfer("B", 1))
+
+	assert.EqualError(t, err1, "Nonce 5 doesn't follow nonce 5 of previous transaction of sender")
+	assert.NoError(t, err2)
+}
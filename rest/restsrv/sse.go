@@ -0,0 +1,97 @@
+package restsrv
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mediacoin-pro/core/common/xlog"
+)
+
+const (
+	contentTypeEventStream = "text/event-stream"
+
+	sseKeepAliveInterval = 15 * time.Second
+	sseWriteTimeout      = 2 * sseKeepAliveInterval // write deadline of event stream (moved ahead by every write)
+)
+
+//	/txs/stream?address=<address>&memo=<memo>
+//
+// streamAddressTxs streams Server-Sent Events "tx" with transactions involving address as they are committed.
+// Data of event is transaction JSON as in /tx/<hash>; id of event is transaction hash.
+func (c *Context) streamAddressTxs() {
+	addr, memo := c.getAddress("")
+	flusher, ok := c.rw.(http.Flusher)
+	c.assertSupported(ok)
+	defer c.acquireSubscription()()
+
+	tips := c.feed.subscribe()
+	defer c.feed.unsubscribe(tips)
+	var next uint64
+	if last := c.bc.LastBlock(); last != nil {
+		next = last.Num + 1
+	}
+
+	h := c.rw.Header()
+	h.Set("Content-Type", contentTypeEventStream)
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // disable buffering of reverse proxy
+	c.extendWriteDeadline(sseWriteTimeout)
+	c.writeHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+	done := c.req.Context().Done()
+	for {
+		select {
+		case <-done:
+			return
+		case <-c.closing:
+			return
+		case <-keepAlive.C:
+			c.extendWriteDeadline(sseWriteTimeout)
+			if _, err := fmt.Fprint(c.rw, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case tip := <-tips:
+			for ; next <= tip; next++ {
+				block, err := c.bc.GetBlock(next)
+				if err != nil || block == nil {
+					xlog.Error.Printf("rest> [%s] sse: can't get block %d: %v", c.reqID, next, err)
+					return
+				}
+				for _, tx := range block.Txs {
+					if !txInvolves(tx, addr, memo) {
+						continue
+					}
+					var v interface{} = tx
+					if c.wantsISOTime() {
+						v = isoTimestamps(v)
+					}
+					if c.wantsCamelCase() {
+						v = camelCaseKeys(v)
+					}
+					data, _ := json.Marshal(v)
+					c.extendWriteDeadline(sseWriteTimeout)
+					if _, err := fmt.Fprintf(c.rw, "id: %s\nevent: tx\ndata: %s\n\n", hex.EncodeToString(tx.Hash()), data); err != nil {
+						xlog.Trace.Printf("rest> [%s] sse: client dropped: %v", c.reqID, err)
+						return
+					}
+					flusher.Flush()
+				}
+			}
+		}
+	}
+}
+
+// extendWriteDeadline sets write deadline of connection to d from now.
+// Long-lived responses (event streams) outlive WriteTimeout of http-server otherwise.
+func (c *Context) extendWriteDeadline(d time.Duration) {
+	if err := http.NewResponseController(c.rw).SetWriteDeadline(time.Now().Add(d)); err != nil {
+		xlog.Trace.Printf("rest> [%s] can't set write deadline: %v", c.reqID, err)
+	}
+}
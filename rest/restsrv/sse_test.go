@@ -0,0 +1,37 @@
+package restsrv
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_extendWriteDeadline(t *testing.T) {
+	s := &Server{}
+	s.setConfig(&Config{})
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		c := newContext(s, req, &statusWriter{ResponseWriter: rw})
+		for i := 0; i < 3; i++ { // stream is open 3 times longer than write timeout
+			c.extendWriteDeadline(200 * time.Millisecond)
+			fmt.Fprintf(c.rw, ": keep-alive %d\n\n", i)
+			c.rw.(http.Flusher).Flush()
+			time.Sleep(100 * time.Millisecond)
+		}
+	}))
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ": keep-alive 0\n\n: keep-alive 1\n\n: keep-alive 2\n\n", string(data))
+}
@@ -0,0 +1,51 @@
+package restsrv
+
+import (
+	"time"
+)
+
+// NetworkStatus is source of state of node networking layer (peers and synchronization)
+type NetworkStatus interface {
+	// Peers returns count of connected peers
+	Peers() int
+
+	// SyncTarget returns height of the best known chain of peers (0 if unknown)
+	SyncTarget() uint64
+}
+
+// staleTipBlocks is count of average block intervals after which node without NetworkStatus is considered syncing
+const staleTipBlocks = 10
+
+type nodeStatus struct {
+	Height        uint64  `json:"height"`
+	IsSyncing     bool    `json:"is_syncing"`
+	TargetHeight  uint64  `json:"target_height,omitempty"` // height of peers (set if node is behind)
+	Peers         *int    `json:"peers"`                   // null if networking layer isn't connected to REST service
+	UptimeSeconds int64   `json:"uptime_seconds"`
+	Version       string  `json:"version"`
+	TipAge        float64 `json:"tip_age"` // seconds since the last block
+}
+
+// nodeStatus handles /status.
+// Without NetworkStatus node is considered syncing if the last block is older than staleTipBlocks average block intervals.
+func (c *Context) nodeStatus() *nodeStatus {
+	res := &nodeStatus{
+		UptimeSeconds: int64(time.Since(c.startTime).Seconds()),
+		Version:       c.Version,
+	}
+	last := c.bc.LastBlock()
+	if last != nil {
+		res.Height = last.Num
+		res.TipAge = time.Since(blockTime(last.Timestamp)).Seconds()
+	}
+	if c.Network != nil {
+		peers := c.Network.Peers()
+		res.Peers = &peers
+		if target := c.Network.SyncTarget(); target > res.Height {
+			res.IsSyncing, res.TargetHeight = true, target
+		}
+	} else if avg := c.avgBlockTime(100); last == nil || avg > 0 && res.TipAge > (staleTipBlocks*avg).Seconds() {
+		res.IsSyncing = true
+	}
+	return res
+}
@@ -0,0 +1,190 @@
+package restsrv
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is a key-value storage of cached responses.
+// Default implementation is in-process; multi-instance deployments can plug in a shared backend (e.g. Redis).
+type Cache interface {
+	Get(key string) (value []byte, ok bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// IdempotencyStore keeps results of write requests by client idempotency keys
+type IdempotencyStore interface {
+	// Reserve marks key as in progress. It returns false and stored result (if any) when key is already known.
+	Reserve(key string, ttl time.Duration) (ok bool, result []byte)
+
+	// Complete stores result of request for the reserved key
+	Complete(key string, result []byte, ttl time.Duration)
+
+	// Release removes reservation of key (request failed), so request with the key can be repeated
+	Release(key string)
+}
+
+// RateLimiter limits frequency of requests by key (e.g. client IP)
+type RateLimiter interface {
+	// Allow takes one request token of key. It returns false and time to wait when the limit is exceeded.
+	Allow(key string) (ok bool, retryAfter time.Duration)
+}
+
+//----------------------- in-memory implementations --------------------
+const memStorageMaxItems = 100000
+
+type memItem struct {
+	value   []byte
+	expires time.Time
+}
+
+type memCache struct {
+	mx    sync.Mutex
+	items map[string]memItem
+}
+
+// NewMemCache returns in-process Cache
+func NewMemCache() Cache {
+	return &memCache{items: map[string]memItem{}}
+}
+
+func (m *memCache) Get(key string) ([]byte, bool) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	it, ok := m.items[key]
+	if !ok || time.Now().After(it.expires) {
+		return nil, false
+	}
+	return it.value, true
+}
+
+func (m *memCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	if len(m.items) >= memStorageMaxItems {
+		m.purge()
+	}
+	m.items[key] = memItem{value, time.Now().Add(ttl)}
+}
+
+// purge removes expired items or (if there are none) arbitrary half of items
+func (m *memCache) purge() {
+	now := time.Now()
+	for k, it := range m.items {
+		if now.After(it.expires) {
+			delete(m.items, k)
+		}
+	}
+	for k := range m.items {
+		if len(m.items) < memStorageMaxItems/2 {
+			break
+		}
+		delete(m.items, k)
+	}
+}
+
+type memIdempotencyStore struct {
+	mx    sync.Mutex
+	cache *memCache
+}
+
+// NewMemIdempotencyStore returns in-process IdempotencyStore
+func NewMemIdempotencyStore() IdempotencyStore {
+	return &memIdempotencyStore{cache: NewMemCache().(*memCache)}
+}
+
+func (m *memIdempotencyStore) Reserve(key string, ttl time.Duration) (bool, []byte) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	if res, ok := m.cache.Get(key); ok {
+		return false, res
+	}
+	m.cache.Set(key, nil, ttl)
+	return true, nil
+}
+
+func (m *memIdempotencyStore) Complete(key string, result []byte, ttl time.Duration) {
+	m.cache.Set(key, result, ttl)
+}
+
+func (m *memIdempotencyStore) Release(key string) {
+	m.cache.mx.Lock()
+	defer m.cache.mx.Unlock()
+	delete(m.cache.items, key)
+}
+
+type memRateLimiter struct {
+	rate    float64 // tokens per second
+	burst   float64
+	mx      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewMemRateLimiter returns in-process token-bucket RateLimiter allowing rate requests per second per key
+// with bursts up to burst requests. Zero rate means unlimited.
+func NewMemRateLimiter(rate float64, burst int) RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &memRateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+func (m *memRateLimiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	if m.rate <= 0 {
+		return true, 0
+	}
+	b := m.buckets[key]
+	if b == nil {
+		if len(m.buckets) >= memStorageMaxItems {
+			m.purge(now)
+		}
+		b = &tokenBucket{tokens: m.burst, updated: now}
+		m.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.updated).Seconds() * m.rate
+	if b.tokens > m.burst {
+		b.tokens = m.burst
+	}
+	b.updated = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / m.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// SetRate changes rate and burst of limiter; tokens of existing buckets are kept (up to new burst)
+func (m *memRateLimiter) SetRate(rate float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.rate, m.burst = rate, float64(burst)
+	for _, b := range m.buckets {
+		if b.tokens > m.burst {
+			b.tokens = m.burst
+		}
+	}
+}
+
+// purge removes buckets which are full again
+func (m *memRateLimiter) purge(now time.Time) {
+	for k, b := range m.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*m.rate >= m.burst {
+			delete(m.buckets, k)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package restsrv
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/mediacoin-pro/core/common/xlog"
+)
+
+const contentTypeNDJSON = "application/x-ndjson"
+
+// streamMeta is pagination metadata sent after streamed items
+type streamMeta struct {
+	NextOffset string `json:"next_offset,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// writeNDJSON streams result items as newline-delimited JSON objects.
+// Pagination metadata of *Response is sent in HTTP-trailers X-Next-Offset, X-Next-Cursor
+// if client accepts trailers (header "TE: trailers"), otherwise as the last line {"meta":{...}}.
+func (c *Context) writeNDJSON(v interface{}) {
+	var meta *streamMeta
+	if r, ok := v.(*Response); ok {
+		v, meta = r.Results, &streamMeta{r.NextOffset, r.NextCursor}
+	}
+	useTrailers := meta != nil && strings.Contains(c.req.Header.Get("TE"), "trailers")
+
+	h := c.rw.Header()
+	h.Set("Content-Type", contentTypeNDJSON)
+	if useTrailers {
+		h.Set("Trailer", "X-Next-Offset, X-Next-Cursor")
+	}
+	c.writeHeader(http.StatusOK)
+
+	flusher, _ := c.rw.(http.Flusher)
+	enc := json.NewEncoder(c.rw)
+	write := func(v interface{}) bool {
+		if c.wantsISOTime() {
+			v = isoTimestamps(v)
+		}
+		if c.wantsCamelCase() {
+			v = camelCaseKeys(v)
+		}
+		if err := enc.Encode(v); err != nil {
+			xlog.Error.Printf("rest> [%s] http-response-error: %v", c.reqID, err)
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+	if items := reflect.ValueOf(v); items.Kind() == reflect.Slice {
+		for i := 0; i < items.Len(); i++ {
+			if !write(items.Index(i).Interface()) {
+				return
+			}
+		}
+	} else if v != nil && !write(v) {
+		return
+	}
+	if useTrailers {
+		h.Set("X-Next-Offset", meta.NextOffset)
+		h.Set("X-Next-Cursor", meta.NextCursor)
+	} else if meta != nil {
+		write(struct {
+			Meta *streamMeta `json:"meta"`
+		}{meta})
+	}
+}
@@ -0,0 +1,126 @@
+package restsrv
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	errTooManySubscriptions      = errors.New("503 - Too many subscriptions, try later")
+	errTooManySubscriptionsPerIP = errors.New("503 - Too many subscriptions from client IP")
+	errSlowConsumer              = errors.New("slow consumer")
+)
+
+// subscriptions counts concurrent streaming connections (WebSocket, SSE) in total and by client IP
+type subscriptions struct {
+	mx    sync.Mutex
+	total int
+	byIP  map[string]int
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{byIP: map[string]int{}}
+}
+
+func (s *subscriptions) acquire(ip string, maxTotal, maxPerIP int) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	if maxTotal > 0 && s.total >= maxTotal {
+		return errTooManySubscriptions
+	}
+	if maxPerIP > 0 && s.byIP[ip] >= maxPerIP {
+		return errTooManySubscriptionsPerIP
+	}
+	s.total++
+	s.byIP[ip]++
+	return nil
+}
+
+func (s *subscriptions) release(ip string) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.total--
+	if s.byIP[ip]--; s.byIP[ip] <= 0 {
+		delete(s.byIP, ip)
+	}
+}
+
+// stats returns count of subscriptions and count of client IPs with subscriptions
+func (s *subscriptions) stats() (total, clients int) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.total, len(s.byIP)
+}
+
+// acquireSubscription registers streaming connection of client or aborts request with 503-response over the limits.
+// Returned func releases the subscription.
+func (c *Context) acquireSubscription() (release func()) {
+	ip := c.clientIP()
+	if err := c.subs.acquire(ip, c.cfg.MaxSubscriptions, c.cfg.MaxSubscriptionsPerIP); err != nil {
+		c.abort(err, http.StatusServiceUnavailable)
+	}
+	return func() { c.subs.release(ip) }
+}
+
+// wsSender writes messages to WebSocket connection from buffered queue (Config.SubscriptionBuffer messages)
+type wsSender struct {
+	conn  *websocket.Conn
+	queue chan interface{}
+	done  chan struct{} // closed when sender failed
+	err   error
+}
+
+func newWSSender(conn *websocket.Conn, bufSize int) *wsSender {
+	if bufSize <= 0 {
+		bufSize = 1
+	}
+	s := &wsSender{
+		conn:  conn,
+		queue: make(chan interface{}, bufSize),
+		done:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *wsSender) run() {
+	for v := range s.queue {
+		if err := wsWriteVar(s.conn, v); err != nil {
+			s.err = err
+			close(s.done)
+			for range s.queue { // drain
+			}
+			return
+		}
+	}
+}
+
+// send enqueues message waiting for free space in queue (backpressure)
+func (s *wsSender) send(v interface{}) error {
+	select {
+	case s.queue <- v:
+		return nil
+	case <-s.done:
+		return s.err
+	}
+}
+
+// trySend enqueues message; slow consumer with full queue is dropped (returns errSlowConsumer)
+func (s *wsSender) trySend(v interface{}) error {
+	select {
+	case s.queue <- v:
+		return nil
+	case <-s.done:
+		return s.err
+	default:
+		return errSlowConsumer
+	}
+}
+
+// close stops sender after queued messages are written
+func (s *wsSender) close() {
+	close(s.queue)
+}
@@ -0,0 +1,55 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// timestampFields are JSON fields of blockchain objects with timestamps in microseconds
+var timestampFields = map[string]bool{
+	"timestamp": true,
+	"blockts":   true,
+	"time":      true,
+}
+
+// wantsISOTime returns true if client asks to render timestamps as RFC3339 strings (param "time_format=iso")
+func (c *Context) wantsISOTime() bool {
+	return c.getStr("time_format", "") == "iso"
+}
+
+// isoTimestamps returns JSON-value with timestamp fields rendered as RFC3339 strings in UTC
+func isoTimestamps(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj interface{}
+	if dec.Decode(&obj) != nil {
+		return v
+	}
+	return isoTimestampsValue(obj)
+}
+
+func isoTimestampsValue(obj interface{}) interface{} {
+	switch val := obj.(type) {
+	case []interface{}:
+		for i, item := range val {
+			val[i] = isoTimestampsValue(item)
+		}
+	case map[string]interface{}:
+		for k, item := range val {
+			if n, ok := item.(json.Number); ok && timestampFields[strings.Replace(strings.ToLower(k), "_", "", -1)] {
+				if ts, err := n.Int64(); err == nil {
+					val[k] = blockTime(ts).Format(time.RFC3339Nano)
+					continue
+				}
+			}
+			val[k] = isoTimestampsValue(item)
+		}
+	}
+	return obj
+}
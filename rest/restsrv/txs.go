@@ -0,0 +1,246 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+)
+
+// confirmations returns count of blocks confirming transaction (0 for pending transaction)
+func (c *Context) confirmations(tx *chain.Transaction) uint64 {
+	last := c.bc.LastBlock()
+	if last == nil || tx.BlockNum == 0 && tx.BlockTs == 0 || tx.BlockNum > last.Num {
+		return 0
+	}
+	return last.Num - tx.BlockNum + 1
+}
+
+type txWithStatus struct {
+	*chain.Transaction
+	Confirmations uint64 `json:"confirmations"`
+	Confirmed     bool   `json:"confirmed"`
+}
+
+func (c *Context) txWithStatus(tx *chain.Transaction) *txWithStatus {
+	n := c.confirmations(tx)
+	return &txWithStatus{tx, n, n > 0}
+}
+
+type txConfirmations struct {
+	BlockNum      uint64 `json:"block_num"`
+	Confirmations uint64 `json:"confirmations"`
+	Confirmed     bool   `json:"confirmed"`
+	Pending       bool   `json:"pending,omitempty"` // transaction is in mempool
+}
+
+// txConfirmations handles /tx/<hash>/confirmations
+func (c *Context) txConfirmations(txHash []byte) *txConfirmations {
+	tx, err := c.bc.TransactionByHash(txHash)
+	c.assert(err)
+	if tx != nil {
+		s := c.txWithStatus(tx)
+		return &txConfirmations{tx.BlockNum, s.Confirmations, s.Confirmed, false}
+	}
+	for _, tx := range c.submittedPending() {
+		if bytes.Equal(tx.Hash(), txHash) {
+			return &txConfirmations{Pending: true}
+		}
+	}
+	c.assertFound(false)
+	return nil
+}
+
+// txsByRef handles /tx/by-ref?ref=<value>&address=<recipient>.
+// It returns transfers to recipient with memo (numeric ref) or comment equal to ref.
+func (c *Context) txsByRef() []*txWithStatus {
+	ref := c.getStr("ref", "")
+	addr, memo := c.getAddress("")
+	if ref == "" {
+		c.assert(errEmptyRef)
+	}
+	res := []*txWithStatus{}
+	found := map[string]bool{}
+	scan := func(memo uint64, match func(tr *txobj.SimpleTransfer, out *txobj.TransferOutput) bool) {
+		c.scanAddressTxs(assets.MDC, addr, memo, false, maxScanTxs, func(tx *chain.Transaction) bool {
+			tr, ok := tx.TxObject().(*txobj.SimpleTransfer)
+			if !ok || found[string(tx.Hash())] {
+				return true
+			}
+			for _, out := range tr.Outs {
+				if bytes.Equal(out.To, addr) && match(tr, out) {
+					found[string(tx.Hash())] = true
+					res = append(res, c.txWithStatus(tx))
+					break
+				}
+			}
+			return true
+		})
+	}
+	if n, err := strconv.ParseUint(ref, 0, 64); err == nil && n != 0 && memo == 0 {
+		// numeric reference is memo of payment, so search memo-subaddress by index
+		scan(n, func(_ *txobj.SimpleTransfer, out *txobj.TransferOutput) bool { return out.ToMemo == n })
+	}
+	// reference can also be sent in comment of payment (to base address by numeric reference)
+	scan(memo, func(tr *txobj.SimpleTransfer, out *txobj.TransferOutput) bool {
+		return tr.Comment == ref && (memo == 0 || out.ToMemo == memo)
+	})
+	return res
+}
+
+// addressTxs handles /txs?address=<address> and /address/<address>/txs:
+// page of transactions of address (with counterparty if param "counterparty" is given)
+func (c *Context) addressTxs(addr []byte, memo uint64) *Response {
+	asset := c.getAsset("asset")
+	offset := c.getOffset()
+	limit := c.getLimit()
+	orderDesc := c.getOrderDesc()
+	var txs []*chain.Transaction
+	var ofst uint64
+	var err error
+	cp := c.getStr("counterparty", "")
+	if cp != "" {
+		cpAddr, cpMemo, e := c.addressByStr(cp)
+		c.assert(e)
+		txs, ofst = c.txsWithCounterparty(asset, addr, memo, cpAddr, cpMemo, offset, limit, orderDesc)
+	} else {
+		txs, ofst, err = c.transactionsByAddr(asset, addr, memo, offset, limit, orderDesc)
+	}
+	resp := NewResponse(txs, ofst, err)
+	if err == nil && ofst != 0 {
+		resp.NextCursor = c.sealOffsetCursor(ofst)
+	}
+	if err == nil && c.withEncodings() {
+		resp.Encodings = c.addressEncodings(addr, memo)
+	}
+	if currency := c.getStr("price_asset", ""); err == nil && currency != "" {
+		resp.Results = c.priceTxs(txs, asset, addr, memo, currency)
+	}
+	return resp
+}
+
+// txsWithCounterparty returns page of transactions of address where the other party is counterparty (in either direction).
+// It scans address history from offset until limit is reached or maxScanTxs are scanned
+// and returns offset of the first transaction which is not returned (0 if history is scanned to the end).
+func (c *Context) txsWithCounterparty(asset, addr []byte, memo uint64, cpAddr []byte, cpMemo uint64, offset uint64, limit int64, desc bool) ([]*chain.Transaction, uint64) {
+	res := []*chain.Transaction{}
+	if limit <= 0 {
+		return res, offset
+	}
+	next := c.scanAddressTxsFrom(asset, addr, memo, offset, desc, maxScanTxs, func(tx *chain.Transaction) bool {
+		if txInvolves(tx, cpAddr, cpMemo) {
+			res = append(res, tx)
+		}
+		return int64(len(res)) < limit
+	})
+	return res, next
+}
+
+// placeholderKey signs transfers built only to estimate fee
+var placeholderKey = crypto.NewPrivateKeyBySecret("mdc-rest-estimate-fee")
+
+var errZeroAmount = errors.New("400 - Amount must be positive")
+
+type feeEstimate struct {
+	Asset    string      `json:"asset"`
+	Amount   bignum.Int  `json:"amount"`
+	Fee      bignum.Int  `json:"fee"`
+	FeeAsset string      `json:"fee_asset"`
+	Total    *bignum.Int `json:"total,omitempty"` // amount + fee (only if fee is paid in asset of transfer)
+}
+
+// estimateFee handles /estimate-fee?address=<address>&amount=<num>&asset=<asset>.
+// Transfer is built as by /new-transfer with placeholder sender and isn't put to mempool.
+func (c *Context) estimateFee() *feeEstimate {
+	toAddr, toMemo := c.getAddress("")
+	amount := c.getAmount("amount")
+	if amount.Sign() <= 0 {
+		c.assert(errZeroAmount)
+	}
+	asset := c.getAsset("asset")
+	comment := c.getStr("comment", "")
+
+	tx := txobj.NewSimpleTransfer(c.bc, placeholderKey, asset, amount, 0, toAddr, toMemo, comment, 0)
+	res := &feeEstimate{
+		Asset:    hex.EncodeToString(asset),
+		Amount:   amount,
+		Fee:      txFee(c.bc.Cfg, tx),
+		FeeAsset: hex.EncodeToString(feeAsset),
+	}
+	if bytes.Equal(asset, feeAsset) {
+		total := amount.Add(res.Fee)
+		res.Total = &total
+	}
+	return res
+}
+
+const maxTxIDLen = 16 // max count of hex digits of transaction id (uint64); transaction hash has 64 digits
+
+var errInvalidTxID = fmt.Errorf("400 - Invalid transaction id: expected 1..%d hex digits (or transaction hash of 64 hex digits)", maxTxIDLen)
+
+// parseTxID returns transaction id given as hex string
+func parseTxID(s string) (uint64, error) {
+	if s == "" || len(s) > maxTxIDLen {
+		return 0, errInvalidTxID
+	}
+	id, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, errInvalidTxID
+	}
+	return id, nil
+}
+
+const maxBatchTxs = 100 // max count of transactions of /txs/batch
+
+var errTooManyBatchTxs = fmt.Errorf("400 - Too many transactions (max %d)", maxBatchTxs)
+
+type batchTx struct {
+	Ref   string             `json:"ref"` // hash or id of transaction as requested
+	Tx    *chain.Transaction `json:"tx"`  // nil if transaction is not found or ref is invalid
+	Error string             `json:"error,omitempty"`
+}
+
+// txsBatch handles /txs/batch?txs=<hash|id>,<hash|id>,...
+// Results are in order of refs; errors of refs are returned per item.
+func (c *Context) txsBatch() []*batchTx {
+	refs := strings.Split(c.getStr("txs", ""), ",")
+	if len(refs) > maxBatchTxs {
+		c.assert(errTooManyBatchTxs)
+	}
+	res := make([]*batchTx, 0, len(refs))
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		item := &batchTx{Ref: ref}
+		var err error
+		if s := strings.TrimPrefix(ref, "0x"); len(s) == 64 {
+			var txHash []byte
+			if txHash, err = hex.DecodeString(s); err == nil {
+				item.Tx, err = c.bc.TransactionByHash(txHash)
+			}
+		} else {
+			var txID uint64
+			if txID, err = parseTxID(s); err == nil {
+				item.Tx, err = c.bc.TransactionByID(txID)
+			}
+		}
+		if err == nil && item.Tx == nil {
+			err = err404
+		}
+		if err != nil {
+			item.Error = err.Error()
+		}
+		res = append(res, item)
+	}
+	return res
+}
@@ -0,0 +1,67 @@
+package restsrv
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTxID(t *testing.T) {
+	id, err := parseTxID("1f")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0x1f, id)
+}
+
+func TestParseTxID_16Digits(t *testing.T) {
+	id, err := parseTxID("ffffffffffffffff")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, uint64(0xffffffffffffffff), id)
+}
+
+func TestParseTxID_17Digits(t *testing.T) {
+	_, err := parseTxID("10000000000000000")
+
+	assert.Equal(t, errInvalidTxID, err)
+	assert.Equal(t, 400, statusForError(err))
+}
+
+func TestParseTxID_Overflow(t *testing.T) {
+	_, err := parseTxID("fffffffffffffffff0") // > max uint64
+
+	assert.Equal(t, errInvalidTxID, err)
+}
+
+func TestParseTxID_Invalid(t *testing.T) {
+	_, err1 := parseTxID("")
+	_, err2 := parseTxID("xyz")
+
+	assert.Equal(t, errInvalidTxID, err1)
+	assert.Equal(t, errInvalidTxID, err2)
+}
+
+func TestRouteTxID_Boundary(t *testing.T) {
+	hash := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	assert.True(t, reTxHash.MatchString("/tx/"+hash))
+	assert.True(t, reTxID.MatchString("/tx/"+hash[:16]))
+	assert.True(t, reTxID.MatchString("/tx/"+hash[:17])) // rejected by parseTxID with 400 instead of 404
+	assert.False(t, reTxID.MatchString("/tx/by-ref"))
+}
+
+func TestContext_estimateFee_OtherAsset(t *testing.T) {
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().StrAddress()
+	c, _ := newTestAssetsContext(t, "/estimate-fee?amount=100&asset=0a0b&address="+to)
+	c.bc = c.index.bc
+
+	res := c.estimateFee()
+
+	assert.Equal(t, "0a0b", res.Asset)
+	assert.Equal(t, "100", res.Amount.String())
+	assert.Equal(t, 1, res.Fee.Sign())
+	assert.Equal(t, hex.EncodeToString(feeAsset), res.FeeAsset)
+	assert.Nil(t, res.Total) // fee is paid in MDC
+}
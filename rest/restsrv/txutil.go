@@ -0,0 +1,131 @@
+package restsrv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/common/bin"
+)
+
+// txInvolves returns true if address (+memo) is sender or recipient of transaction
+func txInvolves(tx *chain.Transaction, addr []byte, memo uint64) bool {
+	if tx.Sender != nil && bytes.Equal(tx.Sender.Address(), addr) {
+		return true
+	}
+	if tr, ok := tx.TxObject().(*txobj.SimpleTransfer); ok {
+		for _, out := range tr.Outs {
+			if bytes.Equal(out.To, addr) && (memo == 0 || out.ToMemo == memo) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// feeAsset is asset of transaction fees
+var feeAsset = assets.MDC
+
+// txFee returns fee paid by transaction as blockchain charges it on verification (tx.Verify(cfg)):
+// every transaction of sender pays fixed fee cfg.TxFee, transactions without sender (emission) pay nothing
+func txFee(cfg *chain.Config, tx *chain.Transaction) bignum.Int {
+	if tx.Sender == nil {
+		return bignum.NewInt(0)
+	}
+	return cfg.TxFee
+}
+
+// txSize returns size of binary-encoded transaction
+func txSize(tx *chain.Transaction) int64 {
+	n, _ := io.Copy(ioutil.Discard, bin.NewBuffer(nil, tx))
+	return n
+}
+
+// unsubmittedTx is signed transaction which is not put to mempool (dry run);
+// client submits it by /put-tx (Raw is body of request)
+type unsubmittedTx struct {
+	*chain.Transaction
+	Hash      string `json:"hash"`
+	Raw       string `json:"raw"`       // binary-encoded transaction in hex
+	Submitted bool   `json:"submitted"` // always false
+}
+
+// writeUnsubmittedTx writes signed transaction which is not put to mempool (binary response is encoded transaction)
+func (c *Context) writeUnsubmittedTx(tx *chain.Transaction) {
+	if c.wantsBinary() {
+		c.WriteVar(tx)
+		return
+	}
+	c.WriteVar(&unsubmittedTx{
+		Transaction: tx,
+		Hash:        hex.EncodeToString(tx.Hash()),
+		Raw:         hex.EncodeToString(bin.Encode(tx)),
+	})
+}
+
+// txTransferAmounts returns amounts of asset received and sent by address (+memo) in transaction
+func txTransferAmounts(tx *chain.Transaction, asset, addr []byte, memo uint64) (in, out bignum.Int) {
+	tr, _ := tx.TxObject().(*txobj.SimpleTransfer)
+	return transferAmounts(txSender(tx), tr, asset, addr, memo)
+}
+
+// txBalanceChange returns amounts of asset received and spent by address (+memo) in transaction
+// including fee paid by sender. Balance history is computed by it.
+func txBalanceChange(cfg *chain.Config, tx *chain.Transaction, asset, addr []byte, memo uint64) (in, out bignum.Int) {
+	tr, _ := tx.TxObject().(*txobj.SimpleTransfer)
+	return balanceChange(txSender(tx), txFee(cfg, tx), tr, asset, addr, memo)
+}
+
+// txSender returns address of sender of transaction (nil if transaction has no sender)
+func txSender(tx *chain.Transaction) []byte {
+	if tx.Sender == nil {
+		return nil
+	}
+	return tx.Sender.Address()
+}
+
+// transferAmounts returns amounts of asset received and sent by address (+memo) in transfer tr (nil - no transfer)
+func transferAmounts(sender []byte, tr *txobj.SimpleTransfer, asset, addr []byte, memo uint64) (in, out bignum.Int) {
+	in, out = bignum.NewInt(0), bignum.NewInt(0)
+	if tr == nil {
+		return
+	}
+	isSender := sender != nil && bytes.Equal(sender, addr)
+	for _, o := range tr.Outs {
+		if !bytes.Equal(o.Asset, asset) {
+			continue
+		}
+		if isSender {
+			out = out.Add(o.Amount)
+		}
+		if bytes.Equal(o.To, addr) && (memo == 0 || o.ToMemo == memo) {
+			in = in.Add(o.Amount)
+		}
+	}
+	return
+}
+
+// balanceChange returns amounts of asset received and spent by address (+memo) in transaction of sender with fee
+func balanceChange(sender []byte, fee bignum.Int, tr *txobj.SimpleTransfer, asset, addr []byte, memo uint64) (in, out bignum.Int) {
+	in, out = transferAmounts(sender, tr, asset, addr, memo)
+	if sender != nil && bytes.Equal(sender, addr) && bytes.Equal(asset, feeAsset) {
+		out = out.Add(fee)
+	}
+	return
+}
+
+// txFeeRate returns transaction fee per byte
+func txFeeRate(cfg *chain.Config, tx *chain.Transaction) float64 {
+	size := txSize(tx)
+	if size == 0 {
+		return 0
+	}
+	fee, _ := new(big.Float).SetInt(txFee(cfg, tx).BigInt()).Float64()
+	return fee / float64(size)
+}
@@ -0,0 +1,30 @@
+package restsrv
+
+import (
+	"testing"
+
+	"github.com/mediacoin-pro/core/chain/assets"
+	"github.com/mediacoin-pro/core/chain/bcstore"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/common/bignum"
+	"github.com/mediacoin-pro/core/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestChain returns empty blockchain storage with default config
+func newTestChain(t *testing.T) *bcstore.ChainStorage {
+	return bcstore.NewChainStorage(t.TempDir()+"/bc", nil)
+}
+
+func TestTxFee_SimpleTransfer(t *testing.T) {
+	bc := newTestChain(t)
+	prv := crypto.NewPrivateKeyBySecret("sender")
+	to := crypto.NewPrivateKeyBySecret("recipient").PublicKey().Address()
+	tx := txobj.NewSimpleTransfer(bc, prv, assets.MDC, bignum.NewInt(assets.Coin), 0, to, 0, "", 0)
+
+	fee := txFee(bc.Cfg, tx)
+
+	assert.NoError(t, tx.Verify(bc.Cfg))
+	assert.Equal(t, 1, fee.Sign())
+	assert.True(t, txFeeRate(bc.Cfg, tx) > 0)
+}
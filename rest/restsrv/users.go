@@ -0,0 +1,164 @@
+package restsrv
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/mediacoin-pro/core/chain"
+	"github.com/mediacoin-pro/core/chain/txobj"
+	"github.com/mediacoin-pro/core/crypto"
+)
+
+const (
+	defaultUplineDepth = 10
+	maxUplineDepth     = 100
+)
+
+type uplineUser struct {
+	Level   int    `json:"level"` // 1 is direct referrer
+	UserID  string `json:"user_id"`
+	Nick    string `json:"nick"`
+	Address string `json:"address"`
+}
+
+type upline struct {
+	UserID string        `json:"user_id"`
+	Upline []*uplineUser `json:"upline"` // from direct referrer upward
+	Root   bool          `json:"root"`   // chain ends at user without referrer
+	Cycle  bool          `json:"cycle,omitempty"`
+}
+
+// getUser returns user by id or aborts request with 404-response
+func (c *Context) getUser(userID uint64) *txobj.User {
+	user, err := c.bc.UserByID(userID)
+	c.assert(err)
+	c.assertFound(user != nil)
+	return user
+}
+
+// upline handles /user/<id>/upline?depth=<num>
+func (c *Context) upline(userID uint64) *upline {
+	depth := int(c.getInt("depth"))
+	if depth <= 0 {
+		depth = defaultUplineDepth
+	} else if depth > maxUplineDepth {
+		depth = maxUplineDepth
+	}
+	user := c.getUser(userID)
+	res := &upline{UserID: "0x" + user.PublicKey().HexID(), Upline: []*uplineUser{}}
+	seen := map[uint64]bool{user.ID(): true}
+	for level := 1; level <= depth; level++ {
+		if user.ReferrerID == 0 {
+			res.Root = true
+			break
+		}
+		if seen[user.ReferrerID] {
+			res.Cycle = true
+			break
+		}
+		ref, err := c.bc.UserByID(user.ReferrerID)
+		c.assert(err)
+		if ref == nil { // referrer is not registered
+			res.Root = true
+			break
+		}
+		seen[ref.ID()] = true
+		res.Upline = append(res.Upline, &uplineUser{
+			Level:   level,
+			UserID:  "0x" + ref.PublicKey().HexID(),
+			Nick:    ref.Nick,
+			Address: ref.PublicKey().StrAddress(),
+		})
+		user = ref
+	}
+	if !res.Root && !res.Cycle && user.ReferrerID == 0 {
+		res.Root = true
+	}
+	return res
+}
+
+var errNickNotRegistered = errors.New("404 - Nickname is not registered")
+
+type resolvedName struct {
+	Name      string `json:"name"` // @<nick>
+	Address   string `json:"address"`
+	UserID    string `json:"user_id"`
+	PublicKey string `json:"public_key"`
+}
+
+// resolveName handles /resolve?name=@<nick>
+func (c *Context) resolveName() *resolvedName {
+	nick := strings.TrimPrefix(strings.TrimSpace(c.getStr("name", "")), "@")
+	if nick == "" {
+		c.assert(errors.New("400 - Empty name"))
+	}
+	user, err := c.bc.UserByNick(nick)
+	c.assert(err)
+	if user == nil {
+		c.assert(errNickNotRegistered)
+	}
+	pub := user.PublicKey()
+	return &resolvedName{
+		Name:      "@" + user.Nick,
+		Address:   pub.StrAddress(),
+		UserID:    "0x" + pub.HexID(),
+		PublicKey: pub.String(),
+	}
+}
+
+type addressName struct {
+	Address string `json:"address"`
+	Nick    string `json:"nick,omitempty"` // @<nick> (empty if address isn't registered as user)
+	UserID  string `json:"user_id,omitempty"`
+}
+
+// addressName handles /address/<address>/name.
+// Address with memo (sub-address) has nickname of its account.
+func (c *Context) addressName(addr []byte, memo uint64) *addressName {
+	res := &addressName{Address: crypto.EncodeAddress(addr, memo)}
+	user, err := c.userByAddress(addr)
+	c.assert(err)
+	if user != nil {
+		res.Nick, res.UserID = "@"+user.Nick, "0x"+user.PublicKey().HexID()
+	}
+	return res
+}
+
+type creationTx struct {
+	Hash     string `json:"hash"`
+	BlockNum uint64 `json:"block_num"`
+	BlockIdx int    `json:"block_idx"`
+	BlockTs  int64  `json:"block_ts"`
+}
+
+func newCreationTx(tx *chain.Transaction) *creationTx {
+	return &creationTx{
+		Hash:     hex.EncodeToString(tx.Hash()),
+		BlockNum: tx.BlockNum,
+		BlockIdx: tx.BlockIdx,
+		BlockTs:  tx.BlockTs,
+	}
+}
+
+// userCreationTx handles /user/<id>/creation-tx
+func (c *Context) userCreationTx(userID uint64) *creationTx {
+	tx := c.getUser(userID).Tx()
+	c.assertFound(tx != nil)
+	if stored, err := c.bc.TransactionByHash(tx.Hash()); err == nil && stored != nil {
+		tx = stored // with block position
+	}
+	return newCreationTx(tx)
+}
+
+// assetCreationTx handles /asset/<id>/creation-tx.
+// Creation transaction of asset is the first transaction which transferred asset (emission of asset).
+func (c *Context) assetCreationTx(asset []byte) *creationTx {
+	tx, err := c.index.assetIssue(asset)
+	c.assert(err)
+	c.assertFound(tx != nil)
+	if stored, err := c.bc.TransactionByHash(tx.Hash()); err == nil && stored != nil {
+		tx = stored // with block position
+	}
+	return newCreationTx(tx)
+}
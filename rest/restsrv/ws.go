@@ -0,0 +1,157 @@
+package restsrv
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mediacoin-pro/core/common/bin"
+	"github.com/mediacoin-pro/core/common/xlog"
+)
+
+const (
+	wsProtocolBinary = "binary"
+	wsProtocolJSON   = "json"
+
+	wsWriteTimeout = 10 * time.Second
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 64 * 1024,
+	Subprotocols:    []string{wsProtocolBinary, wsProtocolJSON},
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+func (c *Context) wsUpgrade() (*websocket.Conn, bool) {
+	conn, err := wsUpgrader.Upgrade(c.rw, c.req, nil)
+	c.written = true
+	if err != nil { // upgrader has already replied with http-error
+		xlog.Error.Printf("rest> [%s] ws-upgrade-error: %v", c.reqID, err)
+		return nil, false
+	}
+	return conn, true
+}
+
+// wsReadLoop reads (and discards) client messages; returned channel is closed when client disconnects
+func wsReadLoop(conn *websocket.Conn) <-chan struct{} {
+	closed := make(chan struct{})
+	conn.SetReadLimit(4096)
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	return closed
+}
+
+// wsWriteVar sends object as one message encoded according to negotiated subprotocol (binary by default).
+// A client who does not read messages for wsWriteTimeout is considered dead.
+func wsWriteVar(conn *websocket.Conn, v interface{}) (err error) {
+	var data []byte
+	msgType := websocket.BinaryMessage
+	if conn.Subprotocol() == wsProtocolJSON {
+		msgType = websocket.TextMessage
+		data, err = json.Marshal(v)
+	} else {
+		data, err = ioutil.ReadAll(bin.NewBuffer(nil, v))
+	}
+	if err != nil {
+		return
+	}
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return conn.WriteMessage(msgType, data)
+}
+
+func wsClose(conn *websocket.Conn, code int, text string) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(time.Second))
+}
+
+//	/ws/sync?from=<block-num>
+//
+// serveSync streams blocks from the given number up to the chain tip (backfill),
+// then keeps streaming new blocks as they are committed. Backfill is throttled by Config.WSSyncRate;
+// a client applies backpressure simply by reading slower (sending waits while send-buffer is full).
+// After backfill a client who doesn't keep up with new blocks (send-buffer is full) is dropped.
+func (c *Context) serveSync() {
+	c.streamBlocks("ws-sync", c.getUint("from"))
+}
+
+//	/ws/blocks
+//
+// serveBlocks streams new blocks as they are committed (without backfill).
+// A client who doesn't keep up with new blocks (send-buffer is full) is dropped.
+func (c *Context) serveBlocks() {
+	var next uint64
+	if last := c.bc.LastBlock(); last != nil {
+		next = last.Num + 1
+	}
+	c.streamBlocks("ws-blocks", next)
+}
+
+// streamBlocks streams blocks from number next to WebSocket client until client disconnects
+func (c *Context) streamBlocks(logPrefix string, next uint64) {
+	defer c.acquireSubscription()()
+	conn, ok := c.wsUpgrade()
+	if !ok {
+		return
+	}
+	defer conn.Close()
+
+	closed := wsReadLoop(conn)
+	tips := c.feed.subscribe()
+	defer c.feed.unsubscribe(tips)
+
+	sender := newWSSender(conn, c.cfg.SubscriptionBuffer)
+	defer sender.close()
+
+	var throttle <-chan time.Time
+	if c.cfg.WSSyncRate > 0 {
+		t := time.NewTicker(time.Second / time.Duration(c.cfg.WSSyncRate))
+		defer t.Stop()
+		throttle = t.C
+	}
+	send, live := sender.send, false
+	for {
+		for last := c.bc.LastBlock(); last != nil && next <= last.Num; next++ {
+			block, err := c.bc.GetBlock(next)
+			if err != nil || block == nil {
+				xlog.Error.Printf("rest> [%s] %s: can't get block %d: %v", c.reqID, logPrefix, next, err)
+				wsClose(conn, websocket.CloseInternalServerErr, "can't get block")
+				return
+			}
+			if err := send(block); err == errSlowConsumer {
+				xlog.Trace.Printf("rest> [%s] %s: slow consumer dropped", c.reqID, logPrefix)
+				wsClose(conn, websocket.ClosePolicyViolation, "slow consumer")
+				return
+			} else if err != nil {
+				xlog.Trace.Printf("rest> [%s] %s: client dropped: %v", c.reqID, logPrefix, err)
+				return
+			}
+			if throttle != nil && !live {
+				select {
+				case <-throttle:
+				case <-closed:
+					return
+				case <-c.closing:
+					wsClose(conn, websocket.CloseGoingAway, "server shutdown")
+					return
+				}
+			}
+		}
+		send, live = sender.trySend, true // backfill is done
+		select {
+		case <-tips:
+		case <-closed:
+			return
+		case <-c.closing:
+			wsClose(conn, websocket.CloseGoingAway, "server shutdown")
+			return
+		}
+	}
+}